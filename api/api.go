@@ -0,0 +1,153 @@
+// Package api holds the requests, responses and errors of the JSON API, and
+// the routes shared by the HTTP server and the wasm functions. The server
+// adds the transport: the bodies, the timeouts and the concurrency.
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	srcs "sigmars-garden-solver/internal/srcs"
+)
+
+// Error is the body of the error responses, and a problem of a board for
+// /validate. Status is the HTTP status it is answered with.
+type Error struct {
+	Status  int             `json:"-"`
+	Code    string          `json:"code"`
+	Message string          `json:"message"`
+	Tiles   []srcs.Position `json:"tiles,omitempty"`
+}
+
+func (this *Error) Error() string {
+	return this.Code + ": " + this.Message
+}
+
+func NewError(status int, code string, format string, args ...any) *Error {
+	return &Error{Status: status, Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// ErrorResponse wraps an error as {"error": {"code": ..., "message": ...}}.
+func ErrorResponse(err *Error) any {
+	return struct {
+		Error *Error `json:"error"`
+	}{err}
+}
+
+// SearchError turns the error of a bounded search in a response.
+func SearchError(err error) *Error {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return NewError(http.StatusGatewayTimeout, "timeout", "the search did not end in time")
+	case errors.Is(err, context.Canceled):
+		return NewError(http.StatusServiceUnavailable, "canceled", "the request was canceled")
+	case errors.Is(err, srcs.ErrSearchLimit):
+		return NewError(http.StatusUnprocessableEntity, "search_limit", "the search reached its limit of checks")
+	default:
+		return NewError(http.StatusInternalServerError, "internal", "%v", err)
+	}
+}
+
+type Move struct {
+	X1    int           `json:"x1"`
+	Y1    int           `json:"y1"`
+	X2    int           `json:"x2"`
+	Y2    int           `json:"y2"`
+	Type1 srcs.TileType `json:"type1"`
+	Type2 srcs.TileType `json:"type2"`
+}
+
+func NewMove(action srcs.Action) Move {
+	return Move{action.X1, action.Y1, action.X2, action.Y2, action.Type1, action.Type2}
+}
+
+// ParseBoard checks the board of a request, nil if the request has none.
+func ParseBoard(tiles [][]srcs.TileType) (srcs.Board, *Error) {
+	if tiles == nil {
+		return srcs.Board{}, NewError(http.StatusBadRequest, "missing_board", "the request has no board")
+	}
+	board, err := srcs.ParseBoard(tiles)
+	if err != nil {
+		return srcs.Board{}, NewError(http.StatusBadRequest, "invalid_board", "%v", err)
+	}
+	return board, nil
+}
+
+type SolveResponse struct {
+	Solved   bool   `json:"solved"`
+	Moves    []Move `json:"moves"`
+	Checks   int64  `json:"checks"`
+	Duration string `json:"duration"`
+}
+
+func NewSolveResponse(actions []srcs.Action, checks int64, dur time.Duration) SolveResponse {
+	response := SolveResponse{Solved: len(actions) > 0, Moves: []Move{}, Checks: checks, Duration: dur.String()}
+	for _, action := range actions {
+		response.Moves = append(response.Moves, NewMove(action))
+	}
+	return response
+}
+
+// Solve searches the solution of the board, bound by the context and
+// maxChecks, 0 for no limit.
+func Solve(ctx context.Context, board srcs.Board, maxChecks int64) (SolveResponse, *Error) {
+	actions, n, dur, err := board.SolveWithOptions(srcs.SolveOptions{MaxChecks: maxChecks, Quiet: true, Context: ctx})
+	if err != nil {
+		return SolveResponse{}, SearchError(err)
+	}
+	return NewSolveResponse(actions, n, dur), nil
+}
+
+type ValidateResponse struct {
+	Valid    bool     `json:"valid"`
+	Problems []*Error `json:"problems"`
+}
+
+// Validate reports the problems of the inventory of the board, without
+// searching it.
+func Validate(board srcs.Board) ValidateResponse {
+	response := ValidateResponse{Valid: true, Problems: []*Error{}}
+	for _, deadEnd := range srcs.CheckInventory(board) {
+		response.Valid = false
+		response.Problems = append(response.Problems, &Error{
+			Code:    strings.ReplaceAll(deadEnd.Cause.String(), " ", "_"),
+			Message: deadEnd.Message,
+			Tiles:   deadEnd.Tiles,
+		})
+	}
+	return response
+}
+
+type HintResponse struct {
+	Move Move `json:"move"`
+}
+
+// Hint returns a move after which the board can still be solved, each move
+// being searched within maxChecks, 0 for no limit.
+func Hint(ctx context.Context, board srcs.Board, maxChecks int64) (HintResponse, *Error) {
+	oracle := srcs.NewOracle(maxChecks)
+	oracle.Context = ctx
+	undecided := false
+	for _, move := range oracle.Moves(board) {
+		if move.Outcome == srcs.Outcome_WIN {
+			return HintResponse{Move: NewMove(move.Action)}, nil
+		}
+		undecided = undecided || move.Outcome == srcs.Outcome_UNKNOWN
+	}
+
+	if ctx.Err() != nil {
+		return HintResponse{}, SearchError(ctx.Err())
+	} else if undecided {
+		return HintResponse{}, SearchError(srcs.ErrSearchLimit)
+	}
+	return HintResponse{}, NewError(http.StatusUnprocessableEntity, "no_safe_move", "every move loses")
+}
+
+type GenerateResponse struct {
+	Seed  int64             `json:"seed"`
+	Board [][]srcs.TileType `json:"board"`
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	srcs "sigmars-garden-solver/internal/srcs"
+)
+
+func loadTiles(t *testing.T, path string) [][]srcs.TileType {
+	t.Helper()
+	byteValue, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tiles [][]srcs.TileType
+	if err := json.Unmarshal(byteValue, &tiles); err != nil {
+		t.Fatal(err)
+	}
+	return tiles
+}
+
+// twoLights is a valid board without any move.
+func twoLights() [][]srcs.TileType {
+	tiles := make([][]srcs.TileType, 11)
+	for x := range tiles {
+		n := 6 + x
+		if x > 5 {
+			n = 16 - x
+		}
+		tiles[x] = make([]srcs.TileType, n)
+	}
+	tiles[0][0], tiles[10][5] = srcs.TileType_LIGHT, srcs.TileType_LIGHT
+	return tiles
+}
+
+func checkError(t *testing.T, name string, err *Error, status int, code string) {
+	t.Helper()
+	if err == nil || err.Status != status || err.Code != code {
+		t.Errorf("%s: error %v, want %d %s", name, err, status, code)
+	}
+}
+
+func TestParseBoard(t *testing.T) {
+	_, err := ParseBoard(nil)
+	checkError(t, "no board", err, http.StatusBadRequest, "missing_board")
+	_, err = ParseBoard([][]srcs.TileType{{srcs.TileType_LIGHT}})
+	checkError(t, "one line", err, http.StatusBadRequest, "invalid_board")
+	if _, err := ParseBoard(loadTiles(t, "../inputs/input1.json")); err != nil {
+		t.Errorf("input1: %v", err)
+	}
+}
+
+func TestSolve(t *testing.T) {
+	board, _ := ParseBoard(loadTiles(t, "../inputs/input1.json"))
+	response, err := Solve(context.Background(), board.Clone(), 0)
+	if err != nil || !response.Solved {
+		t.Fatalf("input1: %+v %v", response, err)
+	}
+	for i, move := range response.Moves {
+		if _, err := board.Move(move.X1, move.Y1, move.X2, move.Y2); err != nil {
+			t.Fatalf("move %d: %v", i+1, err)
+		}
+	}
+
+	board, _ = ParseBoard(loadTiles(t, "../testdata/corpus/generated-142.json"))
+	_, err = Solve(context.Background(), board.Clone(), 10)
+	checkError(t, "10 checks", err, http.StatusUnprocessableEntity, "search_limit")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = Solve(ctx, board.Clone(), 0)
+	checkError(t, "canceled", err, http.StatusServiceUnavailable, "canceled")
+
+	board, _ = ParseBoard(twoLights())
+	if response, err := Solve(context.Background(), board, 0); err != nil || response.Solved || response.Moves == nil {
+		t.Errorf("two lights: %+v %v", response, err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	board, _ := ParseBoard(loadTiles(t, "../inputs/input1.json"))
+	if response := Validate(board); !response.Valid || len(response.Problems) != 0 {
+		t.Errorf("input1: %+v", response)
+	}
+	board, _ = ParseBoard(twoLights())
+	response := Validate(board)
+	if response.Valid || len(response.Problems) == 0 || len(response.Problems[0].Tiles) == 0 {
+		t.Errorf("two lights: %+v", response)
+	}
+}
+
+func TestHint(t *testing.T) {
+	board, _ := ParseBoard(loadTiles(t, "../inputs/input1.json"))
+	response, err := Hint(context.Background(), board.Clone(), 1000000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	move := response.Move
+	if _, err := board.Move(move.X1, move.Y1, move.X2, move.Y2); err != nil {
+		t.Errorf("hint %+v: %v", move, err)
+	}
+
+	board, _ = ParseBoard(twoLights())
+	_, err = Hint(context.Background(), board, 1000000)
+	checkError(t, "two lights", err, http.StatusUnprocessableEntity, "no_safe_move")
+}
+
+func TestErrorResponse(t *testing.T) {
+	byteValue, _ := json.Marshal(ErrorResponse(NewError(http.StatusBadRequest, "invalid_json", "at %d", 3)))
+	if want := `{"error":{"code":"invalid_json","message":"at 3"}}`; string(byteValue) != want {
+		t.Errorf("%s, want %s", byteValue, want)
+	}
+}
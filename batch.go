@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+type batchResult struct {
+	File     string `json:"file"`
+	Status   string `json:"status"` // solved, unsolvable, timeout or invalid
+	Error    string `json:"error,omitempty"`
+	Checks   int64  `json:"checks"`
+	Duration string `json:"duration"`
+	Moves    int    `json:"moves"` // length of the solution
+}
+
+type batchReport struct {
+	Results    []batchResult  `json:"results"`
+	Statuses   map[string]int `json:"statuses"` // number of boards by status
+	Checks     int64          `json:"checks"`
+	Duration   string         `json:"duration"` // wall time of the batch
+	WorkerTime string         `json:"worker_time"`
+}
+
+// batchMain solves many boards concurrently. Each board is searched within
+// its own timeout and invalid files are reported instead of stopping the
+// batch. The exit code is the worst status found: invalid, then timeout,
+// then unsolvable.
+func batchMain(args []string) {
+	flags := newFlagSet("batch")
+	workers := flags.Int("workers", 4, "boards solved at the same time")
+	output := flags.String("o", "", "also write the report as JSON in this file")
+	common := addBoardFlags(flags).addSearchFlags(flags).addSolverFlags(flags)
+	flags.Parse(args)
+	common.check()
+
+	if flags.NArg() == 0 || *workers <= 0 {
+		flags.Usage()
+		os.Exit(int(ExitCode_INVALID))
+	}
+	var paths []string
+	for _, arg := range flags.Args() {
+		files, err := batchFiles(arg)
+		if err != nil {
+			fail(ExitCode_INVALID, "%v", err)
+		}
+		paths = append(paths, files...)
+	}
+
+	start := time.Now()
+	results := make([]batchResult, len(paths))
+	durations := make([]time.Duration, len(paths))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < *workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for k := range indexes {
+				results[k], durations[k] = solveFile(paths[k], common)
+			}
+		}()
+	}
+	for k := range paths {
+		indexes <- k
+	}
+	close(indexes)
+	wg.Wait()
+
+	report := batchReport{Results: results, Statuses: map[string]int{}, Duration: time.Since(start).String()}
+	var workerTime time.Duration
+	for k, result := range results {
+		report.Statuses[result.Status]++
+		report.Checks += result.Checks
+		workerTime += durations[k]
+	}
+	report.WorkerTime = workerTime.String()
+
+	if *output != "" {
+		byteValue, _ := json.MarshalIndent(report, "", "  ")
+		if err := ioutil.WriteFile(*output, append(byteValue, '\n'), 0644); err != nil {
+			fail(ExitCode_INVALID, "%v", err)
+		}
+	}
+	switch {
+	case common.json():
+		printJSON(report)
+	case *common.quiet:
+	default:
+		printBatchTable(report)
+	}
+
+	switch {
+	case report.Statuses["invalid"] > 0:
+		os.Exit(int(ExitCode_INVALID))
+	case report.Statuses["timeout"] > 0:
+		os.Exit(int(ExitCode_TIMEOUT))
+	case report.Statuses["unsolvable"] > 0:
+		os.Exit(int(ExitCode_UNSOLVABLE))
+	}
+}
+
+// batchFiles returns the boards of a directory, of a manifest or the board
+// file itself. A manifest lists one board file or directory per line,
+// relative to the manifest, blank lines and lines starting with # ignored.
+func batchFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() || filepath.Ext(path) == ".json" {
+		return boardFiles(path), nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var paths []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !filepath.IsAbs(line) {
+			line = filepath.Join(filepath.Dir(path), line)
+		}
+		paths = append(paths, boardFiles(line)...)
+	}
+	return paths, scanner.Err()
+}
+
+// solveFile solves a board file within the limits of the flags.
+func solveFile(path string, common *boardFlags) (batchResult, time.Duration) {
+	result := batchResult{File: path, Duration: "0s"}
+	board, err := readBoard(path)
+	if err != nil {
+		result.Status, result.Error = "invalid", err.Error()
+		return result, 0
+	}
+
+	opts, cancel := common.solveOptions()
+	actions, n, dur, err := board.SolveWithOptions(opts)
+	cancel()
+
+	result.Checks, result.Duration, result.Moves = n, dur.String(), len(actions)
+	switch {
+	case err != nil:
+		result.Status, result.Error = "timeout", err.Error()
+	case len(actions) == 0:
+		result.Status = "unsolvable"
+	default:
+		result.Status = "solved"
+	}
+	return result, dur
+}
+
+func printBatchTable(report batchReport) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "file\tstatus\tchecks\tduration\tmoves\t")
+	for _, r := range report.Results {
+		if r.Status == "invalid" {
+			fmt.Fprintf(w, "%s\tinvalid: %s\t\t\t\t\n", r.File, r.Error)
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%d\t\n", r.File, r.Status, r.Checks, r.Duration, r.Moves)
+	}
+	w.Flush()
+
+	fmt.Printf("%d boards: %d solved, %d unsolvable, %d timeout, %d invalid, %d checks in %s (%s of solving)\n",
+		len(report.Results), report.Statuses["solved"], report.Statuses["unsolvable"], report.Statuses["timeout"],
+		report.Statuses["invalid"], report.Checks, report.Duration, report.WorkerTime)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// copyFile copies the board to the directory, created if needed.
+func copyFile(t *testing.T, src, dir, name string) string {
+	t.Helper()
+	byteValue, err := ioutil.ReadFile(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, byteValue, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// a manifest lists files and directories relative to it, the boards of a
+// directory are its JSON files
+func TestBatchFiles(t *testing.T) {
+	dir := t.TempDir()
+	boards := filepath.Join(dir, "boards")
+	a := copyFile(t, "inputs/input1.json", boards, "a.json")
+	b := copyFile(t, "inputs/input2.json", boards, "b.json")
+	copyFile(t, "Readme.md", boards, "notes.md")
+	input3, _ := filepath.Abs("inputs/input3.json")
+	manifest := writeFile(t, "manifest.txt", "# boards\n\n"+boards+"\n"+input3+"\n")
+	relative := filepath.Join(dir, "relative.txt")
+	if err := ioutil.WriteFile(relative, []byte("boards/b.json\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{boards, []string{a, b}},
+		{a, []string{a}},
+		{manifest, []string{a, b, input3}},
+		{relative, []string{b}},
+	}
+	for _, test := range tests {
+		if paths, err := batchFiles(test.path); err != nil || !reflect.DeepEqual(paths, test.want) {
+			t.Errorf("%s: %v %v, want %v", test.path, paths, err, test.want)
+		}
+	}
+	if _, err := batchFiles(filepath.Join(dir, "nonexistent.txt")); err == nil {
+		t.Errorf("no error for a missing manifest")
+	}
+}
+
+// input3 takes seconds to solve: it times out on its own while the other
+// boards are solved, the invalid one reported without stopping the batch
+func TestBatchReport(t *testing.T) {
+	dir := t.TempDir()
+	boards := filepath.Join(dir, "boards")
+	solved := copyFile(t, "inputs/input1.json", boards, "a.json")
+	invalid := filepath.Join(boards, "b.json")
+	if err := ioutil.WriteFile(invalid, []byte(`[["white", `), 0644); err != nil {
+		t.Fatal(err)
+	}
+	slow := copyFile(t, "inputs/input3.json", boards, "c.json")
+	unsolvable := copyFile(t, writeTiles(t, map[[2]int]string{{0, 0}: "light", {10, 5}: "light"}), boards, "d.json")
+	output := filepath.Join(dir, "report.json")
+
+	start := time.Now()
+	code := runMain(t, "batch", "-workers", "1", "-timeout", "500ms", "-q", "-o", output, boards)
+	if code != ExitCode_INVALID {
+		t.Errorf("exit code %d, want %d", code, ExitCode_INVALID)
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("the batch took %s", d)
+	}
+
+	byteValue, err := ioutil.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report batchReport
+	if err := json.Unmarshal(byteValue, &report); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{solved: "solved", invalid: "invalid", slow: "timeout", unsolvable: "unsolvable"}
+	if len(report.Results) != len(want) {
+		t.Fatalf("%d results, want %d", len(report.Results), len(want))
+	}
+	var checks int64
+	for _, result := range report.Results {
+		if result.Status != want[result.File] {
+			t.Errorf("%s: %s, want %s", result.File, result.Status, want[result.File])
+		}
+		checks += result.Checks
+	}
+	if !reflect.DeepEqual(report.Statuses, map[string]int{"solved": 1, "invalid": 1, "timeout": 1, "unsolvable": 1}) || report.Checks != checks {
+		t.Errorf("statuses %v, %d checks for %d", report.Statuses, report.Checks, checks)
+	}
+	if report.Results[0].Moves == 0 || report.Results[1].Error == "" {
+		t.Errorf("results %+v", report.Results)
+	}
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	. "sigmars-garden-solver/internal/srcs"
+	"sigmars-garden-solver/solvers"
+	"text/tabwriter"
+	"time"
+)
+
+type benchResult struct {
+	File         string  `json:"file"`
+	Solver       string  `json:"solver"`
+	Status       string  `json:"status"` // solved, unsolvable, timeout, wrong (the solution does not verify) or invalid
+	Error        string  `json:"error,omitempty"`
+	Moves        int     `json:"moves"`
+	Checks       int64   `json:"checks"`
+	Min          string  `json:"min"`
+	Mean         string  `json:"mean"`
+	ChecksPerSec float64 `json:"checks_per_sec"`
+
+	mean time.Duration
+}
+
+// benchMain solves each board several times and prints the fastest and the
+// mean duration of the runs. With -compare, each board is also solved by a
+// second solver and the two are printed side by side.
+func benchMain(args []string) {
+	flags := newFlagSet("bench")
+	runs := flags.Int("n", 3, "runs per board")
+	solverName := flags.String("solver", solvers.All[0].Name, "solver timed, see -list")
+	compareName := flags.String("compare", "", "second solver to compare with, none if empty")
+	list := flags.Bool("list", false, "list the solvers")
+	common := addBoardFlags(flags).addSearchFlags(flags)
+	flags.Parse(args)
+	common.check()
+
+	if *list {
+		for _, solver := range solvers.All {
+			fmt.Printf("%-10s %s\n", solver.Name, solver.Description)
+		}
+		return
+	}
+	if flags.NArg() == 0 || *runs <= 0 {
+		flags.Usage()
+		os.Exit(int(ExitCode_INVALID))
+	}
+	limits := solvers.Limits{Timeout: *common.timeout, MaxChecks: *common.maxChecks}
+	solver, err := solvers.Get(*solverName)
+	if err == nil {
+		err = solver.Bounded(limits)
+	}
+	if err != nil {
+		fail(ExitCode_INVALID, "-solver: %v", err)
+	}
+	var paths []string
+	for _, arg := range flags.Args() {
+		paths = append(paths, boardFiles(arg)...)
+	}
+
+	if *compareName != "" {
+		other, err := solvers.Get(*compareName)
+		if err == nil {
+			err = other.Bounded(limits)
+		}
+		if err != nil {
+			fail(ExitCode_INVALID, "-compare: %v", err)
+		}
+		benchCompare(paths, solver, other, *runs, common)
+		return
+	}
+
+	var results []benchResult
+	invalid := false
+	for _, path := range paths {
+		result := benchBoard(path, solver, *runs, common)
+		invalid = invalid || result.Status == "invalid"
+		results = append(results, result)
+	}
+
+	if common.json() {
+		printJSON(results)
+	} else {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+		fmt.Fprintln(w, "file\tstatus\tmoves\tchecks\tmin\tmean\tchecks/s\t")
+		for _, r := range results {
+			if r.Status == "invalid" {
+				fmt.Fprintf(w, "%s\tinvalid: %s\t\t\t\t\t\t\n", r.File, r.Error)
+				continue
+			}
+			fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%s\t%s\t%.0f\t\n", r.File, r.Status, r.Moves, r.Checks, r.Min, r.Mean, r.ChecksPerSec)
+		}
+		w.Flush()
+	}
+	if invalid {
+		os.Exit(int(ExitCode_INVALID))
+	}
+}
+
+// benchCompare prints the results of two solvers on each board, with the
+// ratios of the second to the first.
+func benchCompare(paths []string, solver, other solvers.Solver, runs int, common *boardFlags) {
+	type comparison struct {
+		File   string      `json:"file"`
+		A      benchResult `json:"a"`
+		B      benchResult `json:"b"`
+		Checks float64     `json:"checks_ratio"` // of b to a, 0 if unknown
+		Time   float64     `json:"time_ratio"`
+	}
+
+	var comparisons []comparison
+	invalid := false
+	var totalA, totalB time.Duration
+	for _, path := range paths {
+		c := comparison{File: path, A: benchBoard(path, solver, runs, common), B: benchBoard(path, other, runs, common)}
+		if c.A.Checks > 0 {
+			c.Checks = float64(c.B.Checks) / float64(c.A.Checks)
+		}
+		if c.A.mean > 0 {
+			c.Time = float64(c.B.mean) / float64(c.A.mean)
+		}
+		invalid = invalid || c.A.Status == "invalid"
+		totalA += c.A.mean
+		totalB += c.B.mean
+		comparisons = append(comparisons, c)
+	}
+
+	if common.json() {
+		printJSON(comparisons)
+	} else {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+		fmt.Fprintf(w, "file\t%[1]s\tchecks\tmean\t%[2]s\tchecks\tmean\tchecks ratio\ttime ratio\t\n", solver.Name, other.Name)
+		for _, c := range comparisons {
+			if c.A.Status == "invalid" {
+				fmt.Fprintf(w, "%s\tinvalid: %s\t\t\t\t\t\t\t\t\n", c.File, c.A.Error)
+				continue
+			}
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t%d\t%s\t%s\t%s\t\n", c.File, c.A.Status, c.A.Checks, c.A.Mean,
+				c.B.Status, c.B.Checks, c.B.Mean, formatRatio(c.Checks), formatRatio(c.Time))
+		}
+		w.Flush()
+		fmt.Printf("total of the means: %s %v, %s %v\n", solver.Name, totalA, other.Name, totalB)
+	}
+	if invalid {
+		os.Exit(int(ExitCode_INVALID))
+	}
+}
+
+func formatRatio(ratio float64) string {
+	if ratio == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("x%.3g", ratio)
+}
+
+// benchBoard times the solver on a board. A run that times out is not
+// repeated.
+func benchBoard(path string, solver solvers.Solver, runs int, common *boardFlags) benchResult {
+	result := benchResult{File: path, Solver: solver.Name}
+	tiles, err := readTiles(path)
+	if err == nil {
+		_, err = ParseBoard(tiles)
+	}
+	if err != nil {
+		result.Status, result.Error = "invalid", err.Error()
+		return result
+	}
+
+	var min, total time.Duration
+	done := 0
+	for done < runs {
+		ctx, cancel := common.context()
+		start := time.Now()
+		run, err := solver.Solve(ctx, tiles, *common.maxChecks)
+		dur := time.Since(start)
+		cancel()
+
+		done++
+		if done == 1 || dur < min {
+			min = dur
+		}
+		total += dur
+		result.Moves, result.Checks, result.Error = len(run.Actions), run.Checks, ""
+		if err != nil {
+			result.Status, result.Error = "timeout", err.Error()
+			break
+		} else if len(run.Actions) == 0 {
+			result.Status = "unsolvable"
+		} else if err := solvers.Verify(tiles, run.Actions); err != nil {
+			result.Status, result.Error = "wrong", err.Error()
+		} else {
+			result.Status = "solved"
+		}
+	}
+
+	result.mean = total / time.Duration(done)
+	result.Min, result.Mean = min.String(), result.mean.String()
+	if result.mean > 0 {
+		result.ChecksPerSec = float64(result.Checks) / result.mean.Seconds()
+	}
+	return result
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	. "sigmars-garden-solver/internal/srcs"
+	"time"
+)
+
+// ExitCode is the status the commands exit with.
+type ExitCode int
+
+const (
+	ExitCode_SOLVED     ExitCode = 0 // the board is solved, or the command succeeded
+	ExitCode_UNSOLVABLE ExitCode = 1 // the board has no solution
+	ExitCode_INVALID    ExitCode = 2 // the input or the flags are invalid
+	ExitCode_TIMEOUT    ExitCode = 3 // the search ended before knowing
+	ExitCode_MISMATCH   ExitCode = 4 // the backends disagree, with solve -crosscheck
+)
+
+// fail prints the error and exits with code.
+func fail(code ExitCode, format string, args ...any) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(int(code))
+}
+
+// readInput reads a file, or the standard input if path is "-" or empty.
+func readInput(path string) ([]byte, error) {
+	if path == "" || path == "-" {
+		return ioutil.ReadAll(os.Stdin)
+	}
+	return ioutil.ReadFile(path)
+}
+
+// readTiles reads the tiles of a board file, see readInput.
+func readTiles(path string) ([][]TileType, error) {
+	byteValue, err := readInput(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var tiles [][]TileType
+	if err := json.Unmarshal(byteValue, &tiles); err != nil {
+		return nil, fmt.Errorf("%s: %w", inputName(path), err)
+	}
+	return tiles, nil
+}
+
+// readBoard reads a board like readTiles and checks it.
+func readBoard(path string) (Board, error) {
+	tiles, err := readTiles(path)
+	if err != nil {
+		return Board{}, err
+	}
+	board, err := ParseBoard(tiles)
+	if err != nil {
+		return Board{}, fmt.Errorf("%s: %w", inputName(path), err)
+	}
+	return board, nil
+}
+
+func inputName(path string) string {
+	if path == "" || path == "-" {
+		return "stdin"
+	}
+	return path
+}
+
+// isCleared tells if every tile of the board was removed.
+func isCleared(board Board) bool {
+	for _, tile := range board.Board {
+		if tile.Type != TileType_EMPTY {
+			return false
+		}
+	}
+	return true
+}
+
+// boardFlags are the flags shared by the commands reading a board. The
+// flags a command does not have are nil.
+type boardFlags struct {
+	format *string
+	quiet  *bool
+
+	// commands searching the board
+	timeout   *time.Duration
+	maxChecks *int64
+
+	// commands running the solver
+	heuristic *string
+	backend   *string
+	verbose   *bool
+}
+
+func addBoardFlags(flags *flag.FlagSet) *boardFlags {
+	return &boardFlags{
+		format: flags.String("format", "text", "output format, text or json"),
+		quiet:  flags.Bool("q", false, "print only the result"),
+	}
+}
+
+func (this *boardFlags) addSearchFlags(flags *flag.FlagSet) *boardFlags {
+	this.timeout = flags.Duration("timeout", 0, "time allowed to the search, 0 for no limit")
+	this.maxChecks = flags.Int64("max-checks", 0, "checks allowed to the search, 0 for no limit")
+	return this
+}
+
+func (this *boardFlags) addSolverFlags(flags *flag.FlagSet) *boardFlags {
+	this.heuristic = flags.String("heuristic", string(Heuristic_ALCHEMY), fmt.Sprintf("order of the pairs tried, one of %v", Heuristics))
+	this.backend = flags.String("backend", string(Backend_DFS), fmt.Sprintf("algorithm of the search, one of %v", Backends))
+	this.verbose = flags.Bool("v", false, "print the progress of the search on stderr")
+	return this
+}
+
+// check exits with ExitCode_INVALID if the flags have invalid values.
+func (this *boardFlags) check() {
+	if *this.format != "text" && *this.format != "json" {
+		fail(ExitCode_INVALID, "-format: %q is neither text nor json", *this.format)
+	}
+	if this.heuristic != nil && !Heuristic(*this.heuristic).IsValid() {
+		fail(ExitCode_INVALID, "-heuristic: %q is not one of %v", *this.heuristic, Heuristics)
+	}
+	if this.backend != nil && !Backend(*this.backend).IsValid() {
+		fail(ExitCode_INVALID, "-backend: %q is not one of %v", *this.backend, Backends)
+	}
+}
+
+func (this *boardFlags) json() bool {
+	return *this.format == "json"
+}
+
+// solveOptions returns the options of a search bound by the flags. cancel
+// must be called once the search is over.
+func (this *boardFlags) solveOptions() (opts SolveOptions, cancel context.CancelFunc) {
+	opts = SolveOptions{MaxChecks: *this.maxChecks, Quiet: true}
+	opts.Context, cancel = this.context()
+	if this.heuristic != nil {
+		opts.Heuristic = Heuristic(*this.heuristic)
+	}
+	if this.backend != nil {
+		opts.Backend = Backend(*this.backend)
+	}
+	if this.verbose != nil && *this.verbose {
+		start, last := time.Now(), time.Now()
+		opts.OnProgress = func(progress Progress) {
+			if time.Since(last) >= time.Second {
+				last = time.Now()
+				fmt.Fprintf(os.Stderr, "%d checks, depth %d, %d tiles left, %v\n", progress.Checks, progress.Depth, progress.Remaining, time.Since(start).Round(time.Second))
+			}
+		}
+	}
+	return opts, cancel
+}
+
+// context returns a context done after the timeout of the flags.
+func (this *boardFlags) context() (context.Context, context.CancelFunc) {
+	if *this.timeout > 0 {
+		return context.WithTimeout(context.Background(), *this.timeout)
+	}
+	return context.WithCancel(context.Background())
+}
+
+// searchExitCode tells how a bounded search ended.
+func searchExitCode(err error) ExitCode {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrSearchLimit) {
+		return ExitCode_TIMEOUT
+	}
+	return ExitCode_INVALID
+}
+
+// printJSON writes v indented on the standard output.
+func printJSON(v any) {
+	byteValue, _ := json.MarshalIndent(v, "", "  ")
+	fmt.Println(string(byteValue))
+}
+
+// inputArg returns the only argument of the command, "-" if there is none.
+func inputArg(flags *flag.FlagSet) string {
+	switch flags.NArg() {
+	case 0:
+		return "-"
+	case 1:
+		return flags.Arg(0)
+	}
+	flags.Usage()
+	os.Exit(int(ExitCode_INVALID))
+	return ""
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sigmars-garden-solver/clickscript"
+	. "sigmars-garden-solver/internal/srcs"
+	"sigmars-garden-solver/screen"
+	"time"
+)
+
+func clicksMain(args []string) {
+	flags := newFlagSet("clicks")
+	calibrationPath := flags.String("calibration", "", "calibration of the screen resolution (json)")
+	format := flags.String("format", string(clickscript.Format_XDOTOOL), fmt.Sprintf("script format, one of %v", clickscript.Formats))
+	delay := flags.Duration("delay", 200*time.Millisecond, "pause after each click")
+	output := flags.String("o", "", "output file, standard output if empty")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 || *calibrationPath == "" {
+		flags.Usage()
+		os.Exit(int(ExitCode_INVALID))
+	}
+
+	cal, err := screen.LoadCalibration(*calibrationPath)
+	if err != nil {
+		fail(ExitCode_INVALID, "%v", err)
+	}
+
+	// the script is written on the standard output, not the progress
+	board := loadBoard(flags.Arg(0))
+	actions, _, _, _ := board.SolveWithOptions(SolveOptions{Quiet: true})
+	if len(actions) == 0 {
+		fmt.Fprintln(os.Stderr, "no solution found")
+		os.Exit(int(ExitCode_UNSOLVABLE))
+	}
+
+	out := os.Stdout
+	if *output != "" {
+		if out, err = os.Create(*output); err != nil {
+			fail(ExitCode_INVALID, "%v", err)
+		}
+		defer out.Close()
+	}
+
+	if err := clickscript.Write(out, clickscript.Format(*format), actions, cal, clickscript.Options{Delay: *delay}); err != nil {
+		fail(ExitCode_INVALID, "%v", err)
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files of testdata/")
+
+// captureStdout returns what f writes on the standard output.
+func captureStdout(t *testing.T, f func()) []byte {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	read := make(chan []byte)
+	go func() {
+		var buf bytes.Buffer
+		io.Copy(&buf, r)
+		read <- buf.Bytes()
+	}()
+	f()
+	w.Close()
+	return <-read
+}
+
+// the board needs more than 100000 checks, the solver would print its
+// progress in the middle of the script if it was not quiet
+func TestClicksScript(t *testing.T) {
+	calibration := filepath.Join(t.TempDir(), "calibration.json")
+	err := ioutil.WriteFile(calibration, []byte(`{"width":1920,"height":1080,"originX":960,"originY":540,"hexSize":33}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := captureStdout(t, func() {
+		clicksMain([]string{"-calibration", calibration, "-delay", "100ms", "testdata/corpus/generated-142.json"})
+	})
+
+	golden := "testdata/clicks/generated-142.sh"
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(golden), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(golden, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := ioutil.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("script of generated-142.json:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,99 @@
+// Package clickscript turns a solution into scripts clicking its tiles on
+// screen, so the automation does not need any per-OS code in the solver.
+package clickscript
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	. "sigmars-garden-solver/internal/srcs"
+	"sigmars-garden-solver/screen"
+)
+
+type Format string
+
+const (
+	Format_XDOTOOL    Format = "xdotool"
+	Format_AUTOHOTKEY Format = "ahk"
+	Format_JSON       Format = "json"
+)
+
+var Formats = []Format{Format_XDOTOOL, Format_AUTOHOTKEY, Format_JSON}
+
+// Click is a mouse click on the tile of a solution step.
+type Click struct {
+	Step int      `json:"step"`
+	X    int      `json:"x"`
+	Y    int      `json:"y"`
+	Tile TileType `json:"tile"`
+	Pos  Position `json:"pos"`
+}
+
+type Options struct {
+	Delay time.Duration // pause after each click
+}
+
+// Clicks converts the actions in clicks, two per action except for the
+// final alchemy tile which is removed alone.
+func Clicks(actions []Action, cal screen.Calibration) []Click {
+	clicks := make([]Click, 0, 2*len(actions))
+	for i, action := range actions {
+		x, y := cal.Pixel(action.X1, action.Y1)
+		clicks = append(clicks, Click{i + 1, x, y, action.Type1, Position{X: action.X1, Y: action.Y1}})
+		if action.X1 != action.X2 || action.Y1 != action.Y2 {
+			x, y := cal.Pixel(action.X2, action.Y2)
+			clicks = append(clicks, Click{i + 1, x, y, action.Type2, Position{X: action.X2, Y: action.Y2}})
+		}
+	}
+	return clicks
+}
+
+// Write writes the script clicking the actions in the given format.
+func Write(w io.Writer, format Format, actions []Action, cal screen.Calibration, opts Options) error {
+	if err := cal.Valid(); err != nil {
+		return err
+	}
+	clicks := Clicks(actions, cal)
+
+	switch format {
+	case Format_XDOTOOL:
+		return writeScript(w, clicks, opts, "#!/bin/sh\n", "# step %d: %s\n",
+			"xdotool mousemove %d %d click 1\n", func(d time.Duration) string { return fmt.Sprintf("sleep %g\n", d.Seconds()) })
+	case Format_AUTOHOTKEY:
+		return writeScript(w, clicks, opts, "CoordMode, Mouse, Screen\n", "; step %d: %s\n",
+			"Click, %d, %d\n", func(d time.Duration) string { return fmt.Sprintf("Sleep, %d\n", d.Milliseconds()) })
+	case Format_JSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(struct {
+			Width   int     `json:"width"`
+			Height  int     `json:"height"`
+			DelayMs int64   `json:"delayMs"`
+			Clicks  []Click `json:"clicks"`
+		}{cal.Width, cal.Height, opts.Delay.Milliseconds(), clicks})
+	default:
+		return fmt.Errorf("clickscript: unknown format %q", format)
+	}
+}
+
+func writeScript(w io.Writer, clicks []Click, opts Options, header, comment, click string, sleep func(time.Duration) string) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString(header)
+	for i, c := range clicks {
+		if i == 0 || clicks[i-1].Step != c.Step {
+			tiles := string(c.Tile)
+			if i+1 < len(clicks) && clicks[i+1].Step == c.Step {
+				tiles += " " + string(clicks[i+1].Tile)
+			}
+			fmt.Fprintf(bw, comment, c.Step, tiles)
+		}
+		fmt.Fprintf(bw, click, c.X, c.Y)
+		if opts.Delay > 0 {
+			bw.WriteString(sleep(opts.Delay))
+		}
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	. "sigmars-garden-solver/internal/srcs"
+)
+
+func dedupMain(args []string) {
+	flags := newFlagSet("dedup")
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(int(ExitCode_INVALID))
+	}
+
+	var paths []string
+	for _, arg := range flags.Args() {
+		paths = append(paths, boardFiles(arg)...)
+	}
+
+	type original struct {
+		path string
+		sym  Symmetry // moves the board to the canonical one
+	}
+	originals := map[string]original{}
+	var keys []string
+	duplicates := map[string][]string{}
+	for _, path := range paths {
+		board, err := readBoard(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			continue
+		}
+		canonical, sym := Canonical(board)
+		key := CanonicalKey(canonical)
+		first, ok := originals[key]
+		if !ok {
+			originals[key] = original{path, sym}
+			keys = append(keys, key)
+			continue
+		}
+		duplicates[key] = append(duplicates[key], fmt.Sprintf("%s (%v of %s)", path, first.sym.Then(sym.Inverse()), first.path))
+	}
+
+	nbDuplicates := 0
+	for _, key := range keys {
+		for _, duplicate := range duplicates[key] {
+			fmt.Printf("%s\n", duplicate)
+			nbDuplicates++
+		}
+	}
+	fmt.Printf("%d boards, %d distinct, %d duplicates\n", len(originals)+nbDuplicates, len(originals), nbDuplicates)
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	. "sigmars-garden-solver/internal/srcs"
+	"sigmars-garden-solver/solvers"
+	"strings"
+)
+
+type diffResult struct {
+	Seed int64 `json:"seed"`
+	solvers.Discrepancy
+	File string `json:"file,omitempty"` // minimized board, with -o
+}
+
+// diffMain runs several solvers on generated boards, scrambled so some
+// have no solution, and reports the boards they disagree on with the
+// smallest board disagreeing the same way. It exits with 1 when a
+// discrepancy is found.
+func diffMain(args []string) {
+	flags := newFlagSet("diff")
+	seed := flags.Int64("seed", 1, "seed of the first board, the next ones use the following seeds")
+	count := flags.Int("n", 20, "number of boards")
+	swaps := flags.Int("swaps", 2, "tiles swapped on each board")
+	names := flags.String("solvers", "srcs,srcs-sat", "solvers compared, see bench -list")
+	output := flags.String("o", "", "write the minimized boards in this directory")
+	common := addBoardFlags(flags).addSearchFlags(flags)
+	flags.Parse(args)
+	common.check()
+
+	if flags.NArg() != 0 || *count <= 0 {
+		flags.Usage()
+		os.Exit(int(ExitCode_INVALID))
+	}
+	limits := solvers.Limits{Timeout: *common.timeout, MaxChecks: *common.maxChecks}
+	var list []solvers.Solver
+	for _, name := range strings.Split(*names, ",") {
+		solver, err := solvers.Get(strings.TrimSpace(name))
+		if err == nil {
+			err = solver.Bounded(limits)
+		}
+		if err != nil {
+			fail(ExitCode_INVALID, "-solvers: %v", err)
+		}
+		list = append(list, solver)
+	}
+	if *output != "" {
+		if err := os.MkdirAll(*output, 0755); err != nil {
+			fail(ExitCode_INVALID, "%v", err)
+		}
+	}
+
+	var results []diffResult
+	for i := 0; i < *count; i++ {
+		s := *seed + int64(i)
+		board, err := Generate(s, GenerateOptions{})
+		if err != nil {
+			fail(ExitCode_INVALID, "seed %d: %v", s, err)
+		}
+		d, found := solvers.Diff(list, solvers.Scramble(board.Tiles(), s, *swaps), limits)
+		if !found {
+			if !common.json() && !*common.quiet {
+				fmt.Printf("seed %d: %s\n", s, formatRuns(d.Runs))
+			}
+			continue
+		}
+
+		result := diffResult{Seed: s, Discrepancy: d}
+		if *output != "" {
+			result.File = filepath.Join(*output, fmt.Sprintf("diff-%d.json", s))
+			if err := ioutil.WriteFile(result.File, []byte(formatTiles(d.Minimized)), 0644); err != nil {
+				fail(ExitCode_INVALID, "%v", err)
+			}
+		}
+		results = append(results, result)
+		if !common.json() {
+			fmt.Printf("seed %d: discrepancy: %s\n", s, formatRuns(d.Runs))
+			fmt.Printf("minimized to %d tiles: %s\n", countTiles(d.Minimized), formatRuns(d.MinimizedRuns))
+			if result.File != "" {
+				fmt.Println(result.File)
+			} else {
+				fmt.Print(formatTiles(d.Minimized))
+			}
+		}
+	}
+
+	if common.json() {
+		if results == nil {
+			results = []diffResult{}
+		}
+		printJSON(results)
+	} else {
+		fmt.Printf("%d boards, %d discrepancies\n", *count, len(results))
+	}
+	if len(results) > 0 {
+		os.Exit(1)
+	}
+}
+
+func formatRuns(runs []solvers.Run) string {
+	var s []string
+	for _, run := range runs {
+		if run.Error != "" {
+			s = append(s, fmt.Sprintf("%s %s (%s)", run.Solver, run.Verdict, run.Error))
+		} else {
+			s = append(s, fmt.Sprintf("%s %s", run.Solver, run.Verdict))
+		}
+	}
+	return strings.Join(s, ", ")
+}
+
+func countTiles(tiles [][]TileType) int {
+	n := 0
+	for _, line := range tiles {
+		for _, tile := range line {
+			if tile != TileType_EMPTY {
+				n++
+			}
+		}
+	}
+	return n
+}
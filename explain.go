@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	. "sigmars-garden-solver/internal/srcs"
+	"sigmars-garden-solver/render"
+	"sigmars-garden-solver/tui"
+)
+
+func explainMain(args []string) {
+	flags := newFlagSet("explain")
+	maxChecks := flags.Int64("max-checks", 10000000, "checks allowed to search the board when no simple cause is found")
+	output := flags.String("o", "", "also draw the board with the tiles of the dead ends outlined, .svg or .png")
+	color := flags.Bool("color", false, "color the board with ANSI escape codes")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(int(ExitCode_INVALID))
+	}
+
+	board := loadBoard(flags.Arg(0))
+	explanation := Explain(board, ExplainOptions{MaxChecks: *maxChecks})
+
+	var marked []Position
+	marks := tui.Marks{}
+	for _, deadEnd := range explanation.DeadEnds {
+		for _, pos := range deadEnd.Tiles {
+			marks[pos] = tui.Mark_PROBLEM
+			marked = append(marked, pos)
+		}
+	}
+
+	fmt.Print(tui.RenderBoard(&board, marks, *color))
+	switch explanation.Outcome {
+	case Outcome_WIN:
+		fmt.Println("the board has a solution")
+	case Outcome_UNKNOWN:
+		fmt.Println("the board could not be decided")
+	default:
+		fmt.Println("the board has no solution")
+	}
+	for _, deadEnd := range explanation.DeadEnds {
+		fmt.Println(deadEnd)
+	}
+
+	if *output != "" {
+		if err := writeExplanation(*output, &board, marked); err != nil {
+			fail(ExitCode_INVALID, "%v", err)
+		}
+	}
+
+	switch explanation.Outcome {
+	case Outcome_LOSS:
+		os.Exit(int(ExitCode_UNSOLVABLE))
+	case Outcome_UNKNOWN:
+		os.Exit(int(ExitCode_TIMEOUT))
+	}
+}
+
+// writeExplanation draws the board with the marked tiles outlined, as PNG or
+// SVG after the extension of path.
+func writeExplanation(path string, board *Board, marked []Position) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	opts := render.Options{Marked: marked}
+	if filepath.Ext(path) == ".png" {
+		return render.PNG(f, board, opts)
+	}
+	return render.SVG(f, board, opts)
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	. "sigmars-garden-solver/internal/srcs"
+	"time"
+)
+
+func generateMain(args []string) {
+	flags := newFlagSet("generate")
+	seed := flags.Int64("seed", time.Now().UnixNano(), "seed of the first board, the next ones use the following seeds")
+	count := flags.Int("n", 1, "number of boards")
+	output := flags.String("o", "", "write the boards in this directory instead of the standard output")
+	flags.Parse(args)
+
+	if flags.NArg() != 0 {
+		flags.Usage()
+		os.Exit(int(ExitCode_INVALID))
+	}
+
+	if *output != "" {
+		if err := os.MkdirAll(*output, 0755); err != nil {
+			fail(ExitCode_INVALID, "%v", err)
+		}
+	}
+
+	for i := 0; i < *count; i++ {
+		board, err := Generate(*seed+int64(i), GenerateOptions{})
+		if err != nil {
+			fail(ExitCode_INVALID, "seed %d: %v", *seed+int64(i), err)
+		}
+
+		if *output == "" {
+			fmt.Print(formatTiles(board.Tiles()))
+			continue
+		}
+		path := filepath.Join(*output, fmt.Sprintf("generated-%d.json", *seed+int64(i)))
+		if err := ioutil.WriteFile(path, []byte(formatTiles(board.Tiles())), 0644); err != nil {
+			fail(ExitCode_INVALID, "%v", err)
+		}
+		fmt.Println(path)
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	. "sigmars-garden-solver/internal/srcs"
+	"strings"
+)
+
+type hintOutput struct {
+	Found bool    `json:"found"`
+	Move  *Action `json:"move,omitempty"`
+}
+
+// hintMain prints the first move keeping the board solvable. It exits with
+// ExitCode_UNSOLVABLE if every move loses, ExitCode_TIMEOUT if some moves
+// could not be told apart within the limits.
+func hintMain(args []string) {
+	flags := newFlagSet("hint")
+	common := addBoardFlags(flags).addSearchFlags(flags)
+	flags.Parse(args)
+	common.check()
+
+	board := loadBoard(inputArg(flags))
+	if isCleared(board) {
+		fail(ExitCode_SOLVED, "the board is already solved")
+	}
+	ctx, cancel := common.context()
+	defer cancel()
+	oracle := NewOracle(*common.maxChecks)
+	oracle.Context = ctx
+
+	var output hintOutput
+	undecided := false
+	for _, move := range oracle.Moves(board) {
+		if move.Outcome == Outcome_WIN {
+			action := move.Action
+			output = hintOutput{Found: true, Move: &action}
+			break
+		}
+		undecided = undecided || move.Outcome == Outcome_UNKNOWN
+	}
+
+	switch {
+	case common.json():
+		printJSON(output)
+	case *common.quiet && output.Found:
+		fmt.Print(SolutionToString([]Action{*output.Move}))
+	case output.Found:
+		fmt.Println("hint:", strings.TrimSpace(SolutionToString([]Action{*output.Move})))
+	case *common.quiet:
+	case undecided:
+		fmt.Println("no safe move found within the limits")
+	default:
+		fmt.Println("every move loses")
+	}
+	if !output.Found && undecided {
+		os.Exit(int(ExitCode_TIMEOUT))
+	} else if !output.Found {
+		os.Exit(int(ExitCode_UNSOLVABLE))
+	}
+}
@@ -0,0 +1,510 @@
+// Package sat is a CDCL SAT solver: conflicts are analysed down to their
+// first unique implication point, learnt as clauses and the search jumps
+// back to the level they assert. Variables are chosen by activity (VSIDS),
+// with their last value, and the search restarts along the Luby sequence.
+package sat
+
+import (
+	"context"
+	"errors"
+	"sort"
+)
+
+// Lit is a literal: Lit(v) is the variable v, -Lit(v) its negation.
+// Variables are numbered from 1, as in DIMACS.
+type Lit int
+
+func (this Lit) Var() int {
+	if this < 0 {
+		return int(-this)
+	}
+	return int(this)
+}
+
+// index of the literal in the watches
+func (this Lit) index() int {
+	if this < 0 {
+		return 2*int(-this) + 1
+	}
+	return 2 * int(this)
+}
+
+var ErrConflictLimit = errors.New("conflict limit reached")
+
+type clause struct {
+	lits    []Lit // the first two are watched, the first is the one implied
+	learnt  bool
+	lbd     int // number of decision levels of a learnt clause when learnt
+	deleted bool
+}
+
+type Solver struct {
+	clauses []*clause
+	learnts []*clause
+	watches [][]*clause // by the index of the literal whose falsity is watched
+
+	assigns  []int8 // by variable: 0 unassigned, 1 true, -1 false
+	level    []int
+	reason   []*clause
+	phase    []bool // last value, tried first
+	activity []float64
+	varInc   float64
+	heap     varHeap
+	seen     []bool
+
+	trail    []Lit
+	trailLim []int // start of each decision level in the trail
+	qhead    int   // next literal of the trail to propagate
+
+	model     []bool
+	unsat     bool // found while adding the clauses
+	conflicts int64
+}
+
+func New() *Solver {
+	this := &Solver{varInc: 1}
+	// variable 0 is unused
+	this.NewVar()
+	return this
+}
+
+// NewVar adds a variable, returned as its positive literal.
+func (this *Solver) NewVar() Lit {
+	v := len(this.assigns)
+	this.assigns = append(this.assigns, 0)
+	this.level = append(this.level, 0)
+	this.reason = append(this.reason, nil)
+	this.phase = append(this.phase, false)
+	this.activity = append(this.activity, 0)
+	this.seen = append(this.seen, false)
+	this.watches = append(this.watches, nil, nil)
+	if v > 0 {
+		this.heap.activity = this.activity
+		this.heap.insert(v)
+	}
+	return Lit(v)
+}
+
+func (this *Solver) NumVars() int {
+	return len(this.assigns) - 1
+}
+
+func (this *Solver) NumClauses() int {
+	return len(this.clauses)
+}
+
+// Conflicts returns the number of conflicts met by the searches.
+func (this *Solver) Conflicts() int64 {
+	return this.conflicts
+}
+
+func (this *Solver) value(lit Lit) int8 {
+	if lit < 0 {
+		return -this.assigns[-lit]
+	}
+	return this.assigns[lit]
+}
+
+// AddClause adds the disjunction of the literals. It must be called
+// between searches, not during one.
+func (this *Solver) AddClause(lits ...Lit) {
+	if this.unsat {
+		return
+	}
+	// a literal next to its copies and its negation
+	lits = append([]Lit{}, lits...)
+	sort.Slice(lits, func(i, j int) bool {
+		return lits[i].Var() < lits[j].Var() || lits[i].Var() == lits[j].Var() && lits[i] < lits[j]
+	})
+	kept := make([]Lit, 0, len(lits))
+	for i, lit := range lits {
+		if lit.Var() == 0 || lit.Var() >= len(this.assigns) {
+			panic("sat: unknown variable")
+		}
+		if i > 0 && lit == lits[i-1] {
+			continue
+		}
+		if i > 0 && lit == -lits[i-1] || this.value(lit) == 1 {
+			return // always true
+		}
+		if this.value(lit) == 0 {
+			kept = append(kept, lit)
+		}
+	}
+
+	switch len(kept) {
+	case 0:
+		this.unsat = true
+	case 1:
+		this.enqueue(kept[0], nil)
+		if this.propagate() != nil {
+			this.unsat = true
+		}
+	default:
+		c := &clause{lits: kept}
+		this.clauses = append(this.clauses, c)
+		this.attach(c)
+	}
+}
+
+func (this *Solver) attach(c *clause) {
+	this.watches[c.lits[0].index()] = append(this.watches[c.lits[0].index()], c)
+	this.watches[c.lits[1].index()] = append(this.watches[c.lits[1].index()], c)
+}
+
+func (this *Solver) decisionLevel() int {
+	return len(this.trailLim)
+}
+
+func (this *Solver) enqueue(lit Lit, reason *clause) {
+	v := lit.Var()
+	if lit > 0 {
+		this.assigns[v] = 1
+	} else {
+		this.assigns[v] = -1
+	}
+	this.level[v] = this.decisionLevel()
+	this.reason[v] = reason
+	this.trail = append(this.trail, lit)
+}
+
+// propagate assigns the literals implied by the trail, returning the
+// clause falsified if there is a conflict.
+func (this *Solver) propagate() *clause {
+	for this.qhead < len(this.trail) {
+		falseLit := -this.trail[this.qhead]
+		this.qhead++
+
+		ws := this.watches[falseLit.index()]
+		kept := ws[:0]
+		var conflict *clause
+		for i, c := range ws {
+			if c.deleted {
+				continue
+			}
+			if conflict != nil {
+				kept = append(kept, ws[i:]...)
+				break
+			}
+			if c.lits[0] == falseLit {
+				c.lits[0], c.lits[1] = c.lits[1], c.lits[0]
+			}
+			if this.value(c.lits[0]) == 1 {
+				kept = append(kept, c)
+				continue
+			}
+
+			moved := false
+			for k := 2; k < len(c.lits); k++ {
+				if this.value(c.lits[k]) != -1 {
+					c.lits[1], c.lits[k] = c.lits[k], c.lits[1]
+					this.watches[c.lits[1].index()] = append(this.watches[c.lits[1].index()], c)
+					moved = true
+					break
+				}
+			}
+			if moved {
+				continue
+			}
+
+			kept = append(kept, c)
+			if this.value(c.lits[0]) == -1 {
+				conflict = c
+			} else {
+				this.enqueue(c.lits[0], c)
+			}
+		}
+		// deleted clauses are dropped from the end too
+		for i := len(kept); i < len(ws); i++ {
+			ws[i] = nil
+		}
+		this.watches[falseLit.index()] = kept
+		if conflict != nil {
+			return conflict
+		}
+	}
+	return nil
+}
+
+// analyze returns the clause learnt from the conflict, its first literal
+// being asserted at the level returned, and its second one of that level.
+func (this *Solver) analyze(conflict *clause) ([]Lit, int, int) {
+	learnt := []Lit{0}
+	pathCount := 0
+	var p Lit
+	i := len(this.trail) - 1
+	for {
+		for _, q := range conflict.lits {
+			if q == p {
+				continue
+			}
+			v := q.Var()
+			if this.seen[v] || this.level[v] == 0 {
+				continue
+			}
+			this.seen[v] = true
+			this.bumpVar(v)
+			if this.level[v] == this.decisionLevel() {
+				pathCount++
+			} else {
+				learnt = append(learnt, q)
+			}
+		}
+		for !this.seen[this.trail[i].Var()] {
+			i--
+		}
+		p = this.trail[i]
+		i--
+		conflict = this.reason[p.Var()]
+		this.seen[p.Var()] = false
+		pathCount--
+		if pathCount == 0 {
+			break
+		}
+	}
+	learnt[0] = -p
+
+	backtrack := 0
+	levels := map[int]bool{this.decisionLevel(): true}
+	for k := 1; k < len(learnt); k++ {
+		v := learnt[k].Var()
+		this.seen[v] = false
+		levels[this.level[v]] = true
+		if this.level[v] > backtrack {
+			backtrack = this.level[v]
+			learnt[1], learnt[k] = learnt[k], learnt[1]
+		}
+	}
+	return learnt, backtrack, len(levels)
+}
+
+func (this *Solver) bumpVar(v int) {
+	this.activity[v] += this.varInc
+	if this.activity[v] > 1e100 {
+		for i := range this.activity {
+			this.activity[i] *= 1e-100
+		}
+		this.varInc *= 1e-100
+	}
+	this.heap.update(v)
+}
+
+// cancelUntil undoes the assignments of the levels above level.
+func (this *Solver) cancelUntil(level int) {
+	if this.decisionLevel() <= level {
+		return
+	}
+	for i := len(this.trail) - 1; i >= this.trailLim[level]; i-- {
+		v := this.trail[i].Var()
+		this.phase[v] = this.assigns[v] == 1
+		this.assigns[v] = 0
+		this.reason[v] = nil
+		this.heap.insert(v)
+	}
+	this.trail = this.trail[:this.trailLim[level]]
+	this.trailLim = this.trailLim[:level]
+	this.qhead = len(this.trail)
+}
+
+// decide returns the unassigned variable of highest activity with its last
+// value, 0 if all are assigned.
+func (this *Solver) decide() Lit {
+	for !this.heap.empty() {
+		v := this.heap.pop()
+		if this.assigns[v] == 0 {
+			if this.phase[v] {
+				return Lit(v)
+			}
+			return -Lit(v)
+		}
+	}
+	return 0
+}
+
+// reduce deletes half of the learnt clauses, those with the most levels,
+// keeping the clauses of two levels and those implying an assignment.
+func (this *Solver) reduce() {
+	sort.SliceStable(this.learnts, func(i, j int) bool {
+		return this.learnts[i].lbd < this.learnts[j].lbd
+	})
+	kept := this.learnts[:len(this.learnts)/2]
+	for _, c := range this.learnts[len(this.learnts)/2:] {
+		v := c.lits[0].Var()
+		if c.lbd <= 2 || this.reason[v] == c && this.value(c.lits[0]) == 1 {
+			kept = append(kept, c)
+		} else {
+			c.deleted = true
+		}
+	}
+	this.learnts = kept
+}
+
+// luby returns the i-th term of the Luby sequence 1 1 2 1 1 2 4 ...
+func luby(i int) int64 {
+	size, seq := 1, 0
+	for size < i+1 {
+		seq++
+		size = 2*size + 1
+	}
+	for size-1 != i {
+		size = (size - 1) / 2
+		seq--
+		i = i % size
+	}
+	return 1 << seq
+}
+
+// Solve searches an assignment satisfying the clauses until ctx is done or
+// maxConflicts more conflicts are met, 0 for no limit. The error is
+// ErrConflictLimit or the error of the context when the search is stopped.
+// The clauses learnt are kept, so clauses can be added and Solve called
+// again.
+func (this *Solver) Solve(ctx context.Context, maxConflicts int64) (bool, error) {
+	this.model = nil
+	if this.unsat {
+		return false, nil
+	}
+	limit := this.conflicts + maxConflicts
+	restarts, restartConflicts := 0, int64(0)
+	maxLearnts := len(this.clauses)/3 + 1000
+
+	for {
+		conflict := this.propagate()
+		if conflict != nil {
+			this.conflicts++
+			restartConflicts++
+			if this.decisionLevel() == 0 {
+				this.unsat = true
+				return false, nil
+			}
+			learnt, backtrack, lbd := this.analyze(conflict)
+			this.cancelUntil(backtrack)
+			if len(learnt) == 1 {
+				this.enqueue(learnt[0], nil)
+			} else {
+				c := &clause{lits: learnt, learnt: true, lbd: lbd}
+				this.learnts = append(this.learnts, c)
+				this.attach(c)
+				this.enqueue(learnt[0], c)
+			}
+			this.varInc /= 0.95
+
+			if maxConflicts > 0 && this.conflicts >= limit {
+				this.cancelUntil(0)
+				return false, ErrConflictLimit
+			}
+			if ctx != nil && this.conflicts%64 == 0 && ctx.Err() != nil {
+				this.cancelUntil(0)
+				return false, ctx.Err()
+			}
+			continue
+		}
+
+		if restartConflicts >= 100*luby(restarts) {
+			restarts++
+			restartConflicts = 0
+			this.cancelUntil(0)
+			continue
+		}
+		if len(this.learnts)-len(this.trail) >= maxLearnts {
+			this.reduce()
+			maxLearnts += maxLearnts / 10
+		}
+
+		lit := this.decide()
+		if lit == 0 {
+			this.model = make([]bool, len(this.assigns))
+			for v := range this.assigns {
+				this.model[v] = this.assigns[v] == 1
+			}
+			this.cancelUntil(0)
+			return true, nil
+		}
+		this.trailLim = append(this.trailLim, len(this.trail))
+		this.enqueue(lit, nil)
+	}
+}
+
+// Value returns the value of the literal in the assignment found by the
+// last Solve.
+func (this *Solver) Value(lit Lit) bool {
+	if lit < 0 {
+		return !this.model[-lit]
+	}
+	return this.model[lit]
+}
+
+// varHeap orders the variables by decreasing activity.
+type varHeap struct {
+	activity []float64
+	heap     []int
+	pos      []int // position of each variable in heap, -1 if it is not
+}
+
+func (this *varHeap) empty() bool {
+	return len(this.heap) == 0
+}
+
+func (this *varHeap) less(i, j int) bool {
+	return this.activity[this.heap[i]] > this.activity[this.heap[j]]
+}
+
+func (this *varHeap) swap(i, j int) {
+	this.heap[i], this.heap[j] = this.heap[j], this.heap[i]
+	this.pos[this.heap[i]] = i
+	this.pos[this.heap[j]] = j
+}
+
+func (this *varHeap) up(i int) {
+	for i > 0 && this.less(i, (i-1)/2) {
+		this.swap(i, (i-1)/2)
+		i = (i - 1) / 2
+	}
+}
+
+func (this *varHeap) down(i int) {
+	for {
+		child := 2*i + 1
+		if child >= len(this.heap) {
+			return
+		}
+		if child+1 < len(this.heap) && this.less(child+1, child) {
+			child++
+		}
+		if !this.less(child, i) {
+			return
+		}
+		this.swap(i, child)
+		i = child
+	}
+}
+
+func (this *varHeap) insert(v int) {
+	for len(this.pos) <= v {
+		this.pos = append(this.pos, -1)
+	}
+	if this.pos[v] >= 0 {
+		return
+	}
+	this.pos[v] = len(this.heap)
+	this.heap = append(this.heap, v)
+	this.up(this.pos[v])
+}
+
+// update moves the variable up after its activity grew.
+func (this *varHeap) update(v int) {
+	if v < len(this.pos) && this.pos[v] >= 0 {
+		this.up(this.pos[v])
+	}
+}
+
+func (this *varHeap) pop() int {
+	v := this.heap[0]
+	this.swap(0, len(this.heap)-1)
+	this.heap = this.heap[:len(this.heap)-1]
+	this.pos[v] = -1
+	if len(this.heap) > 0 {
+		this.down(0)
+	}
+	return v
+}
@@ -0,0 +1,152 @@
+package sat
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+func randomFormula(rng *rand.Rand, vars, clauses int) [][]Lit {
+	formula := make([][]Lit, clauses)
+	for i := range formula {
+		for k := 0; k < 3; k++ {
+			lit := Lit(rng.Intn(vars) + 1)
+			if rng.Intn(2) == 0 {
+				lit = -lit
+			}
+			formula[i] = append(formula[i], lit)
+		}
+	}
+	return formula
+}
+
+func satisfies(formula [][]Lit, value func(Lit) bool) bool {
+	for _, c := range formula {
+		sat := false
+		for _, lit := range c {
+			sat = sat || value(lit)
+		}
+		if !sat {
+			return false
+		}
+	}
+	return true
+}
+
+// bruteForce tells if one of the assignments of the variables satisfies
+// the formula.
+func bruteForce(formula [][]Lit, vars int) bool {
+	for bits := 0; bits < 1<<vars; bits++ {
+		value := func(lit Lit) bool {
+			return (bits>>(lit.Var()-1)&1 == 1) == (lit > 0)
+		}
+		if satisfies(formula, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func newSolver(formula [][]Lit, vars int) *Solver {
+	s := New()
+	for i := 0; i < vars; i++ {
+		s.NewVar()
+	}
+	for _, c := range formula {
+		s.AddClause(c...)
+	}
+	return s
+}
+
+// random 3-SAT around the threshold, about half of them satisfiable
+func TestRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	counts := map[bool]int{}
+	for i := 0; i < 500; i++ {
+		vars := 4 + rng.Intn(11)
+		formula := randomFormula(rng, vars, vars*43/10)
+		s := newSolver(formula, vars)
+		sat, err := s.Solve(context.Background(), 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := bruteForce(formula, vars); sat != want {
+			t.Fatalf("%v: sat %v, want %v", formula, sat, want)
+		}
+		if sat && !satisfies(formula, s.Value) {
+			t.Fatalf("%v: the model does not satisfy the formula", formula)
+		}
+		counts[sat]++
+	}
+	t.Logf("%d satisfiable, %d not", counts[true], counts[false])
+}
+
+// pigeons holes with one pigeon too many, hard for resolution
+func pigeonHoles(s *Solver, holes int) {
+	in := make([][]Lit, holes+1)
+	for p := range in {
+		for h := 0; h < holes; h++ {
+			in[p] = append(in[p], s.NewVar())
+		}
+		s.AddClause(in[p]...)
+	}
+	for h := 0; h < holes; h++ {
+		for p1 := range in {
+			for p2 := p1 + 1; p2 < len(in); p2++ {
+				s.AddClause(-in[p1][h], -in[p2][h])
+			}
+		}
+	}
+}
+
+func TestPigeonHoles(t *testing.T) {
+	s := New()
+	pigeonHoles(s, 6)
+	if sat, err := s.Solve(context.Background(), 0); sat || err != nil {
+		t.Fatalf("sat %v, %v", sat, err)
+	}
+}
+
+func TestLimits(t *testing.T) {
+	s := New()
+	pigeonHoles(s, 10)
+	if _, err := s.Solve(context.Background(), 100); !errors.Is(err, ErrConflictLimit) || s.Conflicts() != 100 {
+		t.Errorf("%v after %d conflicts, want %v after 100", err, s.Conflicts(), ErrConflictLimit)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := s.Solve(ctx, 0); !errors.Is(err, context.Canceled) {
+		t.Errorf("%v, want %v", err, context.Canceled)
+	}
+}
+
+// clauses added between searches, each one excluding the last model
+func TestIncremental(t *testing.T) {
+	s := New()
+	x, y, z := s.NewVar(), s.NewVar(), s.NewVar()
+	s.AddClause(x, y, z)
+	models := 0
+	for {
+		sat, err := s.Solve(context.Background(), 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !sat {
+			break
+		}
+		var block []Lit
+		for _, lit := range []Lit{x, y, z} {
+			if s.Value(lit) {
+				block = append(block, -lit)
+			} else {
+				block = append(block, lit)
+			}
+		}
+		s.AddClause(block...)
+		models++
+	}
+	if models != 7 {
+		t.Errorf("%d models, want 7", models)
+	}
+}
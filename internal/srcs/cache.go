@@ -0,0 +1,166 @@
+package sigmarsolver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// SolutionCache keeps the solutions found by Solve in a directory, one JSON
+// file per board. Boards equal by rotation or mirror share their file: the
+// board is stored in a canonical orientation and the solution is mapped back
+// to the orientation asked for.
+type SolutionCache struct {
+	dir   string
+	stats CacheStats
+}
+
+// CacheStats counts the lookups of a cache since it was created. They are
+// saved along the entries.
+type CacheStats struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+	Stale  int64 `json:"stale"` // entries found whose solution did not replay, solved again
+
+	Entries int   `json:"-"`
+	Size    int64 `json:"-"` // bytes used by the entries
+}
+
+type cacheEntry struct {
+	Board   [][]TileType `json:"board"`
+	Actions [][4]int     `json:"actions"` // x1, y1, x2, y2 on the board of the entry
+	Checks  int64        `json:"checks"`
+}
+
+const cacheStatsFile = "stats.json"
+
+// OpenSolutionCache uses dir as a cache, creating it if needed.
+func OpenSolutionCache(dir string) (*SolutionCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	this := &SolutionCache{dir: dir}
+	if byteValue, err := ioutil.ReadFile(filepath.Join(dir, cacheStatsFile)); err == nil {
+		if err := json.Unmarshal(byteValue, &this.stats); err != nil {
+			return nil, fmt.Errorf("cache %s: %w", cacheStatsFile, err)
+		}
+	}
+	return this, nil
+}
+
+// Solve returns the cached solution of the board, or solves it like
+// SolveWithOptions and stores the solution found. Cached solutions are
+// replayed on the board before being returned, they cost no check. Either
+// way the board is left solved, like with Solve. Boards without solution
+// are not stored.
+func (this *SolutionCache) Solve(board *Board, opts SolveOptions) ([]Action, int64, time.Duration, bool, error) {
+	start := time.Now()
+	canonical, sym := Canonical(*board)
+	tiles := canonical.Tiles()
+	path := filepath.Join(this.dir, tilesKey(tiles)+".json")
+
+	if byteValue, err := ioutil.ReadFile(path); err == nil {
+		var entry cacheEntry
+		if err := json.Unmarshal(byteValue, &entry); err == nil {
+			if actions, played, ok := replayEntry(board, entry, sym.Inverse()); ok {
+				*board = played
+				this.stats.Hits++
+				return actions, 0, time.Since(start), true, this.saveStats()
+			}
+		}
+		this.stats.Stale++
+	} else {
+		this.stats.Misses++
+	}
+
+	actions, n, _, err := board.SolveWithOptions(opts)
+	if err != nil || len(actions) == 0 {
+		return actions, n, time.Since(start), false, firstError(err, this.saveStats())
+	}
+
+	entry := cacheEntry{Board: tiles, Checks: n}
+	for _, action := range sym.Actions(actions) {
+		entry.Actions = append(entry.Actions, [4]int{action.X1, action.Y1, action.X2, action.Y2})
+	}
+	byteValue, _ := json.Marshal(entry)
+	return actions, n, time.Since(start), false, firstError(writeFileAtomic(path, byteValue), this.saveStats())
+}
+
+// Stats returns the counters of the cache and the size of its directory.
+func (this *SolutionCache) Stats() CacheStats {
+	stats := this.stats
+	infos, _ := ioutil.ReadDir(this.dir)
+	for _, info := range infos {
+		if !info.IsDir() && info.Name() != cacheStatsFile && filepath.Ext(info.Name()) == ".json" {
+			stats.Entries++
+			stats.Size += info.Size()
+		}
+	}
+	return stats
+}
+
+func (this CacheStats) String() string {
+	return fmt.Sprintf("%d hits, %d misses, %d stale, %d entries (%d bytes)", this.Hits, this.Misses, this.Stale, this.Entries, this.Size)
+}
+
+func (this *SolutionCache) saveStats() error {
+	byteValue, _ := json.Marshal(this.stats)
+	return writeFileAtomic(filepath.Join(this.dir, cacheStatsFile), byteValue)
+}
+
+// replayEntry maps the actions of the entry through sym and plays them on
+// a copy of the board, checking each one against the rules.
+func replayEntry(board *Board, entry cacheEntry, sym Symmetry) ([]Action, Board, bool) {
+	played := board.Clone()
+	actions := make([]Action, 0, len(entry.Actions))
+	for _, a := range entry.Actions {
+		p1 := sym.Position(Position{a[0], a[1]})
+		p2 := sym.Position(Position{a[2], a[3]})
+		if !IsPossitionValid(p1.X, p1.Y) || !IsPossitionValid(p2.X, p2.Y) {
+			return nil, played, false
+		}
+		action, err := played.Move(p1.X, p1.Y, p2.X, p2.Y)
+		if err != nil {
+			return nil, played, false
+		}
+		actions = append(actions, action)
+	}
+	for _, tile := range played.Board {
+		if tile.Type != TileType_EMPTY {
+			return nil, played, false
+		}
+	}
+	return actions, played, true
+}
+
+// writeFileAtomic writes the file through a temporary file, so that readers
+// never see it half written.
+func writeFileAtomic(path string, byteValue []byte) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(byteValue); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// firstError returns the first error that is not nil.
+func firstError(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package sigmarsolver
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+// the solution stored for a board solves its rotations and mirrors
+func TestCacheSymmetric(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := OpenSolutionCache(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	board := loadBoard(t, knownBoards[0])
+	for sym := Symmetry(0); sym < NbSymmetries; sym++ {
+		moved := sym.Board(board)
+		tiles := moved.Tiles()
+		actions, checks, _, cached, err := cache.Solve(&moved, SolveOptions{Quiet: true})
+		if err != nil || len(actions) == 0 {
+			t.Fatalf("%v: no solution: %v", sym, err)
+		}
+		if cached != (sym != Symmetry_IDENTITY) || cached && checks != 0 {
+			t.Errorf("%v: cached %v with %d checks", sym, cached, checks)
+		}
+		checkSolution(t, fmt.Sprint(sym), tiles, actions)
+	}
+
+	want := CacheStats{Hits: NbSymmetries - 1, Misses: 1, Entries: 1}
+	stats := cache.Stats()
+	stats.Size = 0
+	if stats != want {
+		t.Errorf("stats %+v, want %+v", stats, want)
+	}
+	reopened, err := OpenSolutionCache(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats := reopened.Stats(); stats.Hits != want.Hits || stats.Misses != want.Misses {
+		t.Errorf("stats once reopened %+v, want %+v", stats, want)
+	}
+}
+
+// boards without solution are not stored, entries that do not replay are
+// solved again
+func TestCacheMisses(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := OpenSolutionCache(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lights := NewBoard(tilesOf(map[Position]TileType{{0, 0}: TileType_LIGHT, {10, 5}: TileType_LIGHT}))
+	for i := 0; i < 2; i++ {
+		if actions, _, _, cached, err := cache.Solve(&lights, SolveOptions{Quiet: true}); err != nil || len(actions) > 0 || cached {
+			t.Fatalf("two lights: %d actions, cached %v: %v", len(actions), cached, err)
+		}
+	}
+
+	board := loadBoard(t, knownBoards[0])
+	solved := board.Clone()
+	if _, _, _, _, err := cache.Solve(&solved, SolveOptions{Quiet: true}); err != nil {
+		t.Fatal(err)
+	}
+	entries, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	for _, entry := range entries {
+		if filepath.Base(entry) != cacheStatsFile {
+			if err := ioutil.WriteFile(entry, []byte(`{"actions": [[0, 0, 0, 1]]}`), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	actions, _, _, cached, err := cache.Solve(&board, SolveOptions{Quiet: true})
+	if err != nil || len(actions) == 0 || cached {
+		t.Fatalf("stale entry: %d actions, cached %v: %v", len(actions), cached, err)
+	}
+
+	want := CacheStats{Misses: 3, Stale: 1, Entries: 1}
+	stats := cache.Stats()
+	stats.Size = 0
+	if stats != want {
+		t.Errorf("stats %+v, want %+v", stats, want)
+	}
+}
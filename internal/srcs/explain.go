@@ -0,0 +1,432 @@
+package sigmarsolver
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type DeadEndCause int
+
+const (
+	DeadEnd_INVENTORY      DeadEndCause = iota // tiles without a possible partner
+	DeadEnd_PARITY                             // elements and salts that cannot all be paired
+	DeadEnd_NEVER_UNLOCKED                     // tiles locking each other whatever the order
+	DeadEnd_METAL_CHAIN                        // metals that cannot be removed in their order
+	DeadEnd_CYCLE                              // tiles whose partners are only freed by removing them
+	DeadEnd_QUICKSILVER                        // metals without a quicksilver freed before their turn
+	DeadEnd_SEARCH                             // no cause found but the search, see Explanation.Outcome
+)
+
+func (this DeadEndCause) String() string {
+	switch this {
+	case DeadEnd_INVENTORY:
+		return "inventory"
+	case DeadEnd_PARITY:
+		return "parity"
+	case DeadEnd_NEVER_UNLOCKED:
+		return "never unlocked"
+	case DeadEnd_METAL_CHAIN:
+		return "metal chain"
+	case DeadEnd_CYCLE:
+		return "cycle"
+	case DeadEnd_QUICKSILVER:
+		return "quicksilver"
+	default:
+		return "search"
+	}
+}
+
+type DeadEnd struct {
+	Cause   DeadEndCause
+	Message string
+	Tiles   []Position // tiles involved, sorted
+}
+
+type Explanation struct {
+	Outcome  Outcome // Outcome_LOSS as soon as a dead end is found
+	DeadEnds []DeadEnd
+}
+
+type ExplainOptions struct {
+	MaxChecks int64 // checks allowed to the search when no cause is found, 10000000 if 0
+}
+
+// Explain tells why a board has no solution. The board is checked from the
+// cheapest to the most expensive analysis, stopping at the first one that
+// finds a cause:
+//   - the inventory: light and dark, quicksilvers and metals must match,
+//   - the parity: the elements left odd must be paired with salts,
+//   - the tiles that are never freed, whatever the order of the removals,
+//   - the same with the metals removed in their order,
+//   - the same with each tile removed along with a free partner, which
+//     finds the cycles where partners are only freed by each other,
+//   - the quicksilver: each metal needs its own, freed before the metal and
+//     the metals after it are removed.
+//
+// The tiles of the removal orders are shrunk greedily: no tile of the set
+// can be dropped with the others still blocking each other, though a
+// smaller set may exist elsewhere on the board. When no cause is found, the
+// board is searched with a memory of the dead states: if it has no
+// solution, the tiles that no removal order reaches are reported.
+func Explain(board Board, opts ExplainOptions) Explanation {
+	if opts.MaxChecks <= 0 {
+		opts.MaxChecks = 10000000
+	}
+
+	deadEnds := CheckInventory(board)
+	if len(deadEnds) == 0 {
+		deadEnds = checkRemovalOrder(board)
+	}
+	if len(deadEnds) > 0 {
+		return Explanation{Outcome: Outcome_LOSS, DeadEnds: deadEnds}
+	}
+
+	board = board.Clone()
+	s := newSearch(&board)
+	s.deadStates = map[stateKey]bool{}
+	explanation := Explanation{Outcome: s.outcome(opts.MaxChecks)}
+	switch explanation.Outcome {
+	case Outcome_LOSS:
+		// every reachable state was visited, so these tiles are never removed
+		var neverRemoved []Position
+		for i, tile := range board.Board {
+			if tile.Type != TileType_EMPTY && !s.everRemoved.has(i) {
+				x, y := ToXYPos(i)
+				neverRemoved = append(neverRemoved, Position{x, y})
+			}
+		}
+		explanation.DeadEnds = []DeadEnd{{
+			Cause:   DeadEnd_SEARCH,
+			Message: fmt.Sprintf("no simple cause, every removal order was tried in %d checks and none removes", s.n),
+			Tiles:   neverRemoved,
+		}}
+	case Outcome_UNKNOWN:
+		explanation.DeadEnds = []DeadEnd{{
+			Cause:   DeadEnd_SEARCH,
+			Message: fmt.Sprintf("no simple cause and no solution found in %d checks", s.n),
+		}}
+	}
+	return explanation
+}
+
+// CheckInventory returns the dead ends found by the first and cheapest
+// checks of Explain, on the inventory and the parity of the board.
+func CheckInventory(board Board) []DeadEnd {
+	deadEnds := checkInventory(board)
+	if len(deadEnds) == 0 {
+		deadEnds = checkParity(board)
+	}
+	return deadEnds
+}
+
+func (this DeadEnd) String() string {
+	var positions []string
+	for _, pos := range this.Tiles {
+		positions = append(positions, fmt.Sprintf("(%d,%d)", pos.X, pos.Y))
+	}
+	if len(positions) == 0 {
+		return fmt.Sprintf("%s: %s", this.Cause, this.Message)
+	}
+	return fmt.Sprintf("%s: %s %s", this.Cause, this.Message, strings.Join(positions, " "))
+}
+
+// positionsOf returns the positions of the tiles of the given types.
+func positionsOf(board Board, tileTypes ...TileType) []Position {
+	var positions []Position
+	for i, tile := range board.Board {
+		for _, tileType := range tileTypes {
+			if tile.Type == tileType {
+				x, y := ToXYPos(i)
+				positions = append(positions, Position{x, y})
+			}
+		}
+	}
+	return positions
+}
+
+func checkInventory(board Board) []DeadEnd {
+	var deadEnds []DeadEnd
+	count := map[TileType]int{}
+	for _, tile := range board.Board {
+		count[tile.Type]++
+	}
+
+	if count[TileType_LIGHT] != count[TileType_DARK] {
+		deadEnds = append(deadEnds, DeadEnd{
+			Cause:   DeadEnd_INVENTORY,
+			Message: fmt.Sprintf("%d light for %d dark", count[TileType_LIGHT], count[TileType_DARK]),
+			Tiles:   positionsOf(board, TileType_LIGHT, TileType_DARK),
+		})
+	}
+
+	metals := []TileType{TileType_L1, TileType_L2, TileType_L3, TileType_L4, TileType_L5}
+	nbMetals := 0
+	for _, metal := range metals {
+		nbMetals += count[metal]
+	}
+	if count[TileType_KEY] != nbMetals {
+		deadEnds = append(deadEnds, DeadEnd{
+			Cause:   DeadEnd_INVENTORY,
+			Message: fmt.Sprintf("%d quicksilver for %d metals", count[TileType_KEY], nbMetals),
+			Tiles:   positionsOf(board, append(metals, TileType_KEY)...),
+		})
+	}
+
+	// each metal is unlocked by the removal of the previous one
+	for _, metal := range append(metals, TileType_L6) {
+		stage := metal.GetAlchemyStage()
+		if count[metal] > 1 {
+			deadEnds = append(deadEnds, DeadEnd{
+				Cause:   DeadEnd_INVENTORY,
+				Message: fmt.Sprintf("%d %s, the second one is never unlocked", count[metal], metal),
+				Tiles:   positionsOf(board, metal),
+			})
+		}
+		if stage == AlchemyStage_1 {
+			continue
+		}
+		if previous := (stage - 2).GetNextAlchemyType(); count[metal] > 0 && count[previous] == 0 {
+			deadEnds = append(deadEnds, DeadEnd{
+				Cause:   DeadEnd_METAL_CHAIN,
+				Message: fmt.Sprintf("%s is never unlocked without %s", metal, previous),
+				Tiles:   positionsOf(board, metal),
+			})
+		}
+	}
+	return deadEnds
+}
+
+func checkParity(board Board) []DeadEnd {
+	var odds []TileType
+	for _, element := range []TileType{TileType_CYAN, TileType_ORANGE, TileType_BLUE, TileType_GREEN} {
+		if len(positionsOf(board, element))%2 == 1 {
+			odds = append(odds, element)
+		}
+	}
+	whites := len(positionsOf(board, TileType_WHITE))
+
+	if len(odds) > whites || (whites-len(odds))%2 == 1 {
+		return []DeadEnd{{
+			Cause:   DeadEnd_PARITY,
+			Message: fmt.Sprintf("%d salts for %d odd elements %v", whites, len(odds), odds),
+			Tiles:   positionsOf(board, append(odds, TileType_WHITE)...),
+		}}
+	}
+	return nil
+}
+
+type peelRules struct {
+	metals bool              // metals are removed in their order
+	pairs  bool              // a tile is removed along with a free or removed partner
+	kept   map[Position]bool // tiles never removed
+}
+
+func checkRemovalOrder(board Board) []DeadEnd {
+	var all []Position
+	for i, tile := range board.Board {
+		if tile.Type != TileType_EMPTY {
+			x, y := ToXYPos(i)
+			all = append(all, Position{x, y})
+		}
+	}
+
+	checks := []struct {
+		cause   DeadEndCause
+		rules   peelRules
+		message string
+	}{
+		{DeadEnd_NEVER_UNLOCKED, peelRules{}, "tiles locked until another of them is removed"},
+		{DeadEnd_METAL_CHAIN, peelRules{metals: true}, "tiles locked until another of them is removed, metals in their order"},
+		{DeadEnd_CYCLE, peelRules{metals: true, pairs: true}, "tiles whose partners are only freed by removing one of them"},
+	}
+	for _, check := range checks {
+		if stuck := peel(board, all, check.rules); len(stuck) > 0 {
+			return []DeadEnd{{
+				Cause:   check.cause,
+				Message: check.message,
+				Tiles:   shrinkStuck(board, stuck, check.rules),
+			}}
+		}
+	}
+	return checkQuicksilver(board, all)
+}
+
+// checkQuicksilver matches each metal with a quicksilver that can be freed
+// while the metal and the metals after it are on the board, no quicksilver
+// being used twice. The metals left without one are reported with the
+// quicksilver none of them can use.
+func checkQuicksilver(board Board, all []Position) []DeadEnd {
+	var metals, keys []Position
+	for _, pos := range all {
+		tileType := board.Board[FromXYPos(pos.X, pos.Y)].Type
+		if tileType == TileType_KEY {
+			keys = append(keys, pos)
+		} else if isMetal(tileType) {
+			metals = append(metals, pos)
+		}
+	}
+	stage := func(pos Position) AlchemyStage {
+		return board.Board[FromXYPos(pos.X, pos.Y)].Type.GetAlchemyStage()
+	}
+	sort.Slice(metals, func(i, j int) bool { return stage(metals[i]) < stage(metals[j]) })
+
+	// partners[m] are the indexes in keys of the quicksilver of metals[m]
+	partners := make([][]int, len(metals))
+	for m := range metals {
+		for k, key := range keys {
+			kept := map[Position]bool{key: true}
+			for _, metal := range metals[m:] {
+				kept[metal] = true
+			}
+			if !lockedAmong(board, peel(board, all, peelRules{metals: true, kept: kept}), key) {
+				partners[m] = append(partners[m], k)
+			}
+		}
+	}
+
+	matched := make([]int, len(keys)) // index in metals, -1 if none
+	for k := range matched {
+		matched[k] = -1
+	}
+	var augment func(m int, seen []bool) bool
+	augment = func(m int, seen []bool) bool {
+		for _, k := range partners[m] {
+			if !seen[k] {
+				seen[k] = true
+				if matched[k] < 0 || augment(matched[k], seen) {
+					matched[k] = m
+					return true
+				}
+			}
+		}
+		return false
+	}
+	for m := range metals {
+		seen := make([]bool, len(keys))
+		if augment(m, seen) {
+			continue
+		}
+		// the quicksilver seen are all those of the metals reached from m,
+		// each matched with one of them: one metal is left without
+		tiles := []Position{metals[m]}
+		nbKeys := 0
+		for k, key := range keys {
+			if seen[k] {
+				tiles = append(tiles, metals[matched[k]])
+				nbKeys++
+			} else {
+				tiles = append(tiles, key)
+			}
+		}
+		sortPositions(tiles)
+		return []DeadEnd{{
+			Cause:   DeadEnd_QUICKSILVER,
+			Message: fmt.Sprintf("%d metals for %d quicksilver freed before them, the others are locked by the metals", nbKeys+1, nbKeys),
+			Tiles:   tiles,
+		}}
+	}
+	return nil
+}
+
+// lockedAmong tells if the tile at pos is locked when only the tiles of set
+// are on the board.
+func lockedAmong(board Board, set []Position, pos Position) bool {
+	scratch := Board{AlchemyStage: AlchemyStage_5}
+	for _, other := range set {
+		scratch.Board[FromXYPos(other.X, other.Y)].Type = board.Board[FromXYPos(other.X, other.Y)].Type
+	}
+	return scratch.CheckLockState(pos.X, pos.Y)
+}
+
+// peel removes the tiles of set one by one as soon as they are free, the
+// tiles out of set being removed from the start, and returns the tiles it
+// could not remove. A tile free once stays free when more tiles are
+// removed, so it over-estimates what a solution can remove: the tiles left
+// are left by every solution.
+func peel(board Board, set []Position, rules peelRules) []Position {
+	scratch := Board{AlchemyStage: AlchemyStage_5}
+	left := map[Position]bool{}
+	for _, pos := range set {
+		scratch.Board[FromXYPos(pos.X, pos.Y)].Type = board.Board[FromXYPos(pos.X, pos.Y)].Type
+		left[pos] = true
+	}
+
+	removed := func(tileType TileType) bool {
+		for pos := range left {
+			if scratch.Board[FromXYPos(pos.X, pos.Y)].Type == tileType {
+				return false
+			}
+		}
+		return true
+	}
+	free := func(pos Position) bool {
+		tileType := scratch.Board[FromXYPos(pos.X, pos.Y)].Type
+		if scratch.CheckLockState(pos.X, pos.Y) {
+			return false
+		}
+		stage := tileType.GetAlchemyStage()
+		return !rules.metals || stage <= AlchemyStage_1 || removed((stage - 2).GetNextAlchemyType())
+	}
+	partnered := func(pos Position, freed map[Position]bool) bool {
+		tileType := board.Board[FromXYPos(pos.X, pos.Y)].Type
+		if tileType == TileType_L6 {
+			return true
+		}
+		for i, tile := range board.Board {
+			x, y := ToXYPos(i)
+			other := Position{x, y}
+			if other != pos && CanPair(tileType, tile.Type) && (!left[other] || freed[other]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for progress := true; progress; {
+		progress = false
+		freed := map[Position]bool{}
+		for pos := range left {
+			if !rules.kept[pos] && free(pos) {
+				freed[pos] = true
+			}
+		}
+		for pos := range freed {
+			if !rules.pairs || partnered(pos, freed) {
+				delete(left, pos)
+				scratch.Board[FromXYPos(pos.X, pos.Y)].Type = TileType_EMPTY
+				progress = true
+			}
+		}
+	}
+
+	stuck := make([]Position, 0, len(left))
+	for pos := range left {
+		stuck = append(stuck, pos)
+	}
+	sortPositions(stuck)
+	return stuck
+}
+
+// shrinkStuck drops tiles of stuck as long as the others keep each other
+// stuck without them.
+func shrinkStuck(board Board, stuck []Position, rules peelRules) []Position {
+	for shrunk := true; shrunk; {
+		shrunk = false
+		for i := range stuck {
+			without := append(append([]Position(nil), stuck[:i]...), stuck[i+1:]...)
+			if left := peel(board, without, rules); len(left) > 0 {
+				stuck = left
+				shrunk = true
+				break
+			}
+		}
+	}
+	return stuck
+}
+
+func sortPositions(positions []Position) {
+	sort.Slice(positions, func(i, j int) bool {
+		return positions[i].X < positions[j].X || (positions[i].X == positions[j].X && positions[i].Y < positions[j].Y)
+	})
+}
@@ -0,0 +1,87 @@
+package sigmarsolver
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// tilesOf returns the lines of a board holding the tiles given, the other
+// ones empty.
+func tilesOf(tiles map[Position]TileType) [][]TileType {
+	lines := make([][]TileType, nbLines)
+	for x := range lines {
+		lines[x] = make([]TileType, lineSize[x])
+	}
+	for pos, tileType := range tiles {
+		lines[pos.X][pos.Y] = tileType
+	}
+	return lines
+}
+
+func explainTiles(t *testing.T, tiles [][]TileType) DeadEnd {
+	t.Helper()
+	explanation := Explain(NewBoard(tiles), ExplainOptions{MaxChecks: 1000})
+	if explanation.Outcome != Outcome_LOSS || len(explanation.DeadEnds) != 1 {
+		t.Fatalf("%v %v, want one dead end", explanation.Outcome, explanation.DeadEnds)
+	}
+	return explanation.DeadEnds[0]
+}
+
+func TestExplainInventory(t *testing.T) {
+	deadEnd := explainTiles(t, tilesOf(map[Position]TileType{{0, 0}: TileType_LIGHT, {10, 5}: TileType_LIGHT}))
+	if deadEnd.Cause != DeadEnd_INVENTORY || !reflect.DeepEqual(deadEnd.Tiles, []Position{{0, 0}, {10, 5}}) {
+		t.Errorf("two lights: %v", deadEnd)
+	}
+}
+
+// l1 is surrounded with l2 and l3 on opposite sides: every row of three of
+// its neighbors holds one of them
+func TestExplainMetalChain(t *testing.T) {
+	neighbors := getAllPossibleJoinedTiles(5, 5)
+	tiles := map[Position]TileType{
+		{5, 5}:       TileType_L1,
+		neighbors[0]: TileType_L2,
+		neighbors[3]: TileType_L3,
+		neighbors[1]: TileType_CYAN,
+		neighbors[2]: TileType_CYAN,
+		neighbors[4]: TileType_CYAN,
+		neighbors[5]: TileType_CYAN,
+		{0, 0}:       TileType_KEY,
+		{0, 5}:       TileType_KEY,
+		{10, 0}:      TileType_KEY,
+	}
+	deadEnd := explainTiles(t, tilesOf(tiles))
+	want := []Position{{5, 5}, neighbors[0], neighbors[3]}
+	sortPositions(want)
+	if deadEnd.Cause != DeadEnd_METAL_CHAIN || !reflect.DeepEqual(deadEnd.Tiles, want) {
+		t.Errorf("%v, want %s with %v", deadEnd, DeadEnd_METAL_CHAIN, want)
+	}
+}
+
+// the quicksilver (5,4) is between l5 and l6, the other four go to l1 to l4
+func TestExplainQuicksilver(t *testing.T) {
+	deadEnd := explainTiles(t, loadTiles(t, "../../inputs/input_invalid.json"))
+	want := []Position{{1, 1}, {4, 8}, {5, 3}, {5, 4}, {6, 8}, {7, 1}}
+	if deadEnd.Cause != DeadEnd_QUICKSILVER || !reflect.DeepEqual(deadEnd.Tiles, want) {
+		t.Errorf("%v, want %s with %v", deadEnd, DeadEnd_QUICKSILVER, want)
+	}
+}
+
+// the analyses only report dead ends every solution runs into
+func TestRemovalOrderSolvable(t *testing.T) {
+	for _, path := range knownBoards {
+		if deadEnds := checkRemovalOrder(NewBoard(loadTiles(t, path))); len(deadEnds) > 0 {
+			t.Errorf("%s: %v", path, deadEnds)
+		}
+	}
+	corpus, err := filepath.Glob("../../testdata/corpus/*.json")
+	if err != nil || len(corpus) == 0 {
+		t.Fatalf("no corpus: %v", err)
+	}
+	for _, path := range corpus {
+		if deadEnds := checkRemovalOrder(NewBoard(loadTiles(t, path))); len(deadEnds) > 0 {
+			t.Errorf("%s: %v", path, deadEnds)
+		}
+	}
+}
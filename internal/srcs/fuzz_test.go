@@ -0,0 +1,219 @@
+package sigmarsolver
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func FuzzParseBoard(f *testing.F) {
+	for _, path := range append(knownBoards, "../../inputs/input_invalid.json") {
+		byteValue, err := ioutil.ReadFile(path)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(byteValue)
+	}
+	f.Add([]byte(`[]`))
+	f.Add([]byte(`[[],[],[],[],[],[],[],[],[],[],[]]`))
+	f.Add([]byte(`[["l1","l1","l2","l2","key","key"]]`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var tiles [][]TileType
+		if err := json.Unmarshal(data, &tiles); err != nil {
+			return
+		}
+		board, err := ParseBoard(tiles)
+		if err != nil {
+			return
+		}
+		if !reflect.DeepEqual(board.Tiles(), tiles) {
+			t.Fatalf("Tiles() = %v, parsed %v", board.Tiles(), tiles)
+		}
+		if again := NewBoard(board.Tiles()); !reflect.DeepEqual(again, board) {
+			t.Fatalf("NewBoard(Tiles()) differs from the board parsed")
+		}
+	})
+}
+
+// fuzzTileTypes are the types a byte of the fuzzed boards picks, empty
+// twice
+var fuzzTileTypes = []TileType{
+	TileType_EMPTY, TileType_WHITE, TileType_CYAN, TileType_ORANGE, TileType_BLUE, TileType_GREEN,
+	TileType_LIGHT, TileType_DARK, TileType_KEY, TileType_L1, TileType_L2, TileType_L3,
+	TileType_L4, TileType_L5, TileType_L6, TileType_EMPTY,
+}
+
+var fuzzKnownBoards []Board
+
+// fuzzBoard returns one of the known boards when the first byte is even or
+// the data is too short, else a board of one tile per byte, whatever its
+// inventory. The rest of the data is returned.
+func fuzzBoard(t *testing.T, data []byte) (Board, []byte) {
+	if fuzzKnownBoards == nil {
+		for _, path := range knownBoards {
+			fuzzKnownBoards = append(fuzzKnownBoards, loadBoard(t, path))
+		}
+	}
+	if len(data) == 0 {
+		return fuzzKnownBoards[0].Clone(), data
+	}
+	if data[0]%2 == 0 || len(data) < 1+startLineValue[nbLines] {
+		return fuzzKnownBoards[int(data[0]/2)%len(fuzzKnownBoards)].Clone(), data[1:]
+	}
+	tiles := make([][]TileType, nbLines)
+	k := 1
+	for x := range tiles {
+		for y := 0; y < lineSize[x]; y++ {
+			tiles[x] = append(tiles[x], fuzzTileTypes[data[k]%16])
+			k++
+		}
+	}
+	return NewBoard(tiles), data[k:]
+}
+
+// twoL1Board is a board with l1 twice, whose second l1 used to be removed
+// after the first one, panicking
+func twoL1Board() []byte {
+	data := make([]byte, 1+startLineValue[nbLines])
+	data[0] = 1
+	data[1+FromXYPos(0, 0)], data[1+FromXYPos(0, 2)] = 9, 9
+	data[1+FromXYPos(10, 0)], data[1+FromXYPos(10, 2)] = 8, 8
+	return data
+}
+
+func freeTiles(board *Board) []Position {
+	var free []Position
+	for i, tile := range board.Board {
+		if tile.Type != TileType_EMPTY && !tile.Lock {
+			x, y := ToXYPos(i)
+			free = append(free, Position{x, y})
+		}
+	}
+	return free
+}
+
+// FuzzMoves plays moves, legal or not, and undoes some of them: an illegal
+// move leaves the board as it was, and UndoAction restores the board of
+// before the move.
+func FuzzMoves(f *testing.F) {
+	f.Add([]byte{0, 1, 5, 2, 5, 2, 0})
+	f.Add([]byte{2, 1, 1, 1, 10, 10, 1, 3, 4, 4, 5, 0})
+	f.Add([]byte{6, 1, 0, 0, 0, 1, 0, 1, 11, 11, 3, 3})
+	f.Add(append(twoL1Board(), 1, 1, 1, 11, 1, 1, 1, 3, 11, 3))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		board, data := fuzzBoard(t, data)
+		start := board.Clone()
+		var actions []Action
+		var before []Board
+
+		// 5 bytes per operation: undo if the first one is a multiple of 4, a
+		// move between the positions of the next 4, out of the board by
+		// one, if it is odd, else a move between two of the free tiles
+		for ; len(data) >= 5; data = data[5:] {
+			if data[0]%4 == 0 {
+				if len(actions) == 0 {
+					continue
+				}
+				board.UndoAction(actions[len(actions)-1])
+				if !reflect.DeepEqual(board, before[len(before)-1]) {
+					t.Fatalf("undoing %s does not restore the board", SolutionToString(actions[len(actions)-1:]))
+				}
+				actions, before = actions[:len(actions)-1], before[:len(before)-1]
+				continue
+			}
+
+			x1, y1, x2, y2 := int(data[1]%13)-1, int(data[2]%13)-1, int(data[3]%13)-1, int(data[4]%13)-1
+			if free := freeTiles(&board); data[0]%2 == 0 && len(free) > 0 {
+				p1, p2 := free[int(data[1])%len(free)], free[int(data[3])%len(free)]
+				x1, y1, x2, y2 = p1.X, p1.Y, p2.X, p2.Y
+			}
+			previous := board.Clone()
+			action, err := board.Move(x1, y1, x2, y2)
+			if err != nil {
+				if !reflect.DeepEqual(board, previous) {
+					t.Fatalf("illegal move (%d,%d) (%d,%d) changed the board: %v", x1, y1, x2, y2, err)
+				}
+				continue
+			}
+			actions, before = append(actions, action), append(before, previous)
+		}
+
+		for i := len(actions) - 1; i >= 0; i-- {
+			board.UndoAction(actions[i])
+		}
+		if !reflect.DeepEqual(board, start) {
+			t.Fatalf("undoing %s does not restore the board", SolutionToString(actions))
+		}
+	})
+}
+
+// possibilitiesOf returns the unlocked tiles of the board by type, like
+// the possibilities of a search should be.
+func possibilitiesOf(board *Board) Possibilities {
+	possibilities := Possibilities{}
+	for i, tile := range board.Board {
+		if tile.Type != TileType_EMPTY && !tile.Lock {
+			x, y := ToXYPos(i)
+			possibilities.Insert(tile.Type, Position{x, y})
+		}
+	}
+	return possibilities
+}
+
+func possibilitiesEqual(p1, p2 Possibilities) bool {
+	p1.Sort()
+	p2.Sort()
+	for _, tileType := range fuzzTileTypes {
+		if len(p1[tileType]) != len(p2[tileType]) || len(p1[tileType]) > 0 && !reflect.DeepEqual(p1[tileType], p2[tileType]) {
+			return false
+		}
+	}
+	return true
+}
+
+// FuzzSearch plays the pairs of the solver iterators and undoes them: the
+// possibilities of the search stay the unlocked tiles of the board, and
+// undoLastAction restores the board of before the action.
+func FuzzSearch(f *testing.F) {
+	f.Add([]byte{0, 1, 2, 3, 0, 0, 5})
+	f.Add([]byte{4, 7, 7, 7, 7, 7, 7, 7, 7, 0, 0, 0, 0})
+	f.Add(append([]byte{1}, make([]byte, 120)...))
+	f.Add(append(twoL1Board(), 1, 1))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		board, data := fuzzBoard(t, data)
+		s := newSearch(&board)
+		var before []Board
+
+		// undo if the byte is a multiple of 4, else play the byte-th pair
+		for _, b := range data {
+			if b%4 == 0 {
+				if len(s.actions) == 0 {
+					continue
+				}
+				s.undoLastAction()
+				if !reflect.DeepEqual(board, before[len(before)-1]) {
+					t.Fatalf("undoing %s does not restore the board", SolutionToString(s.actions))
+				}
+				before = before[:len(before)-1]
+			} else {
+				pairs := newIterator(&board, s.possibilities, Heuristic_NONE).foundPossibilities
+				if len(pairs) == 0 {
+					continue
+				}
+				pair := pairs[int(b)%len(pairs)]
+				if err := board.CheckMove(pair.p1.X, pair.p1.Y, pair.p2.X, pair.p2.Y); err != nil {
+					t.Fatalf("after %s the iterator gives %v", SolutionToString(s.actions), err)
+				}
+				before = append(before, board.Clone())
+				s.doAction(pair.p1.X, pair.p1.Y, pair.p2.X, pair.p2.Y)
+			}
+			if !possibilitiesEqual(s.possibilities, possibilitiesOf(&board)) {
+				t.Fatalf("after %s possibilities are %v, the unlocked tiles %v", SolutionToString(s.actions), s.possibilities, possibilitiesOf(&board))
+			}
+		}
+	})
+}
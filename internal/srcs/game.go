@@ -0,0 +1,105 @@
+package sigmarsolver
+
+import (
+	"hash/fnv"
+	"sort"
+)
+
+// Game plays a board move after move, for the programs searching on their
+// own. It keeps the unlocked tiles up to date as the search of Solve does.
+type Game struct {
+	board  Board
+	search *search
+}
+
+func NewGame(board Board) *Game {
+	this := &Game{board: board.Clone()}
+	this.search = newSearch(&this.board)
+	return this
+}
+
+// Board returns a copy of the board as played.
+func (this *Game) Board() Board {
+	return this.board.Clone()
+}
+
+// Actions returns the actions played, in order.
+func (this *Game) Actions() []Action {
+	return append([]Action{}, this.search.actions...)
+}
+
+// LegalMoves returns the pairs Move accepts, the last metal twice, ordered
+// by their positions. Unlike the pairs tried by Solve, they include the
+// salts paired together when an element would be left without partner.
+func (this *Game) LegalMoves() []Action {
+	var free []Position
+	for _, positions := range this.search.possibilities {
+		free = append(free, positions...)
+	}
+	sort.Slice(free, func(i, j int) bool {
+		return FromXYPos(free[i].X, free[i].Y) < FromXYPos(free[j].X, free[j].Y)
+	})
+
+	var moves []Action
+	for i, p1 := range free {
+		for _, p2 := range free[i:] {
+			if this.board.CheckMove(p1.X, p1.Y, p2.X, p2.Y) == nil {
+				moves = append(moves, Action{
+					X1: p1.X, Y1: p1.Y, X2: p2.X, Y2: p2.Y,
+					Type1: this.board.Board[FromXYPos(p1.X, p1.Y)].Type,
+					Type2: this.board.Board[FromXYPos(p2.X, p2.Y)].Type,
+				})
+			}
+		}
+	}
+	return moves
+}
+
+// Apply removes the pair (x1,y1) (x2,y2) if it is legal, in any order.
+func (this *Game) Apply(x1, y1, x2, y2 int) (Action, error) {
+	if err := this.board.CheckMove(x1, y1, x2, y2); err != nil {
+		return Action{}, err
+	}
+	// ApplyAction expects the salt first
+	if this.board.Board[FromXYPos(x2, y2)].Type == TileType_WHITE {
+		x1, y1, x2, y2 = x2, y2, x1, y1
+	}
+	this.search.doAction(x1, y1, x2, y2)
+	return this.search.actions[len(this.search.actions)-1], nil
+}
+
+// Undo takes back the last action, false if none was played.
+func (this *Game) Undo() bool {
+	if len(this.search.actions) == 0 {
+		return false
+	}
+	this.search.undoLastAction()
+	return true
+}
+
+func (this *Game) IsWon() bool {
+	return this.search.remaining == 0
+}
+
+// IsStuck tells if tiles are left but no move is legal.
+func (this *Game) IsStuck() bool {
+	return !this.IsWon() && len(this.LegalMoves()) == 0
+}
+
+// Clone returns a game played independently, with the same actions to undo.
+func (this *Game) Clone() *Game {
+	clone := NewGame(this.board)
+	clone.search.actions = append(clone.search.actions, this.search.actions...)
+	return clone
+}
+
+// Hash identifies the tiles left on the board: games with the same tiles
+// left have the same hash, whatever the moves that led to them.
+func (this *Game) Hash() uint64 {
+	h := fnv.New64a()
+	for _, tile := range this.board.Board {
+		h.Write([]byte(tile.Type))
+		h.Write([]byte{0})
+	}
+	return h.Sum64()
+}
@@ -0,0 +1,85 @@
+package sigmarsolver
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func actionPositions(actions []Action) [][2]Position {
+	var moves [][2]Position
+	for _, action := range actions {
+		moves = append(moves, [2]Position{{action.X1, action.Y1}, {action.X2, action.Y2}})
+	}
+	return moves
+}
+
+func TestGameLegalMoves(t *testing.T) {
+	for _, path := range knownBoards {
+		start := loadBoard(t, path)
+		rng := rand.New(rand.NewSource(1))
+		for walk := 0; walk < 20; walk++ {
+			game := NewGame(start)
+			for {
+				board := game.Board()
+				moves := game.LegalMoves()
+				if want := legalMoves(&board); !reflect.DeepEqual(actionPositions(moves), want) {
+					t.Fatalf("%s: after %s legal moves %v, want %v", path, SolutionToString(game.Actions()), actionPositions(moves), want)
+				}
+				if len(moves) == 0 {
+					if game.IsStuck() == game.IsWon() {
+						t.Fatalf("%s: no legal move, stuck %v and won %v", path, game.IsStuck(), game.IsWon())
+					}
+					break
+				}
+
+				move := moves[rng.Intn(len(moves))]
+				hash := game.Hash()
+				clone := game.Clone()
+				if _, err := game.Apply(move.X2, move.Y2, move.X1, move.Y1); err != nil {
+					t.Fatalf("%s: %v", path, err)
+				}
+				if !reflect.DeepEqual(clone.Board(), board) || clone.Hash() != hash {
+					t.Fatalf("%s: playing the game changed its clone", path)
+				}
+				if rng.Intn(4) == 0 {
+					game.Undo()
+					if !reflect.DeepEqual(game.Board(), board) || game.Hash() != hash {
+						t.Fatalf("%s: undoing %s does not restore the board", path, SolutionToString([]Action{move}))
+					}
+					// play on the clone, then undo all on it
+					game = clone
+				}
+			}
+			for game.Undo() {
+			}
+			if !reflect.DeepEqual(game.Board(), start) {
+				t.Fatalf("%s: undoing all the actions does not restore the board", path)
+			}
+		}
+	}
+}
+
+func TestGameSolution(t *testing.T) {
+	board := loadBoard(t, "../../inputs/input1.json")
+	solved := board.Clone()
+	actions, _, _, err := solved.SolveWithOptions(SolveOptions{Quiet: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	game := NewGame(board)
+	for _, action := range actions {
+		if _, err := game.Apply(action.X1, action.Y1, action.X2, action.Y2); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !game.IsWon() || game.IsStuck() || len(game.LegalMoves()) != 0 {
+		t.Errorf("won %v, stuck %v after the solution", game.IsWon(), game.IsStuck())
+	}
+	if !game.Undo() || game.IsWon() || len(game.LegalMoves()) != 1 {
+		t.Error("undo does not take back the last move")
+	}
+	if _, err := game.Apply(0, 0, 0, 0); err == nil {
+		t.Error("applied an illegal move")
+	}
+}
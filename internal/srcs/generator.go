@@ -0,0 +1,160 @@
+package sigmarsolver
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+type GenerateOptions struct {
+	Attempts  int             // boards tried before giving up, 100 if 0
+	MaxChecks int64           // checks allowed to Solve a board, 1000000 if 0
+	Context   context.Context // give up when it is done, nil for no limit
+}
+
+type pair struct {
+	type1, type2 TileType
+}
+
+// orbits of the tiles under the rotations of 60 degrees, the middle tile
+// excepted: 15 orbits of 6 tiles
+var rotationOrbits = func() [][]Position {
+	var orbits [][]Position
+	seen := map[Position]bool{}
+	for i := 0; i < startLineValue[nbLines]; i++ {
+		x, y := ToXYPos(i)
+		pos := Position{x, y}
+		if seen[pos] || (x == nbLines/2 && y == nbLines/2) {
+			continue
+		}
+		orbit := make([]Position, 0, 6)
+		for n := 0; n < 6; n++ {
+			rotated := rotatePosition(pos, n)
+			seen[rotated] = true
+			orbit = append(orbit, rotated)
+		}
+		orbits = append(orbits, orbit)
+	}
+	return orbits
+}()
+
+// Generate deals a board with the standard inventory (8 of each element,
+// 4 salts, 4 of light and dark, 5 quicksilvers and the 6 metals) on a layout
+// symmetric by rotation, with the last metal in the middle.
+//
+// Like the game, the board is built backward: the pairs are placed in the
+// reverse order of a solution, each one only where both its tiles are free.
+// The result is still checked with Solve, boards it cannot solve within
+// MaxChecks are dropped.
+func Generate(seed int64, opts GenerateOptions) (Board, error) {
+	attempts := opts.Attempts
+	if attempts <= 0 {
+		attempts = 100
+	}
+	maxChecks := opts.MaxChecks
+	if maxChecks <= 0 {
+		maxChecks = 1000000
+	}
+	rng := rand.New(rand.NewSource(seed))
+
+	for attempt := 0; attempt < attempts; attempt++ {
+		if opts.Context != nil && opts.Context.Err() != nil {
+			return Board{}, opts.Context.Err()
+		}
+		tiles, ok := dealBoard(rng)
+		if !ok {
+			continue
+		}
+		board := NewBoard(tiles)
+		solved := board.Clone()
+		if actions, _, _, err := solved.SolveWithOptions(SolveOptions{MaxChecks: maxChecks, Quiet: true, Context: opts.Context}); err == nil && len(actions) > 0 {
+			return board, nil
+		}
+	}
+	return Board{}, fmt.Errorf("generate: no solvable board found in %d attempts", attempts)
+}
+
+// removalOrder returns the pairs of the standard inventory in the order a
+// solution would remove them.
+func removalOrder(rng *rand.Rand) []pair {
+	var pairs []pair
+
+	elements := []TileType{TileType_CYAN, TileType_ORANGE, TileType_BLUE, TileType_GREEN}
+	elementPairs := map[TileType]int{}
+	for _, element := range elements {
+		elementPairs[element] = 4
+	}
+
+	// each salt is paired with an element or with another salt
+	whites := 4
+	for whites > 0 && rng.Intn(2) == 0 {
+		element := elements[rng.Intn(len(elements))]
+		elementPairs[element]--
+		pairs = append(pairs, pair{TileType_WHITE, element}, pair{TileType_WHITE, element})
+		whites -= 2
+	}
+	for ; whites > 0; whites -= 2 {
+		pairs = append(pairs, pair{TileType_WHITE, TileType_WHITE})
+	}
+	for _, element := range elements {
+		for i := 0; i < elementPairs[element]; i++ {
+			pairs = append(pairs, pair{element, element})
+		}
+	}
+	for i := 0; i < 4; i++ {
+		pairs = append(pairs, pair{TileType_LIGHT, TileType_DARK})
+	}
+	rng.Shuffle(len(pairs), func(i, j int) { pairs[i], pairs[j] = pairs[j], pairs[i] })
+
+	// the metals keep their order, at random steps
+	metals := []TileType{TileType_L1, TileType_L2, TileType_L3, TileType_L4, TileType_L5}
+	steps := rng.Perm(len(pairs) + len(metals))[:len(metals)]
+	sort.Ints(steps)
+	for i, step := range steps {
+		pairs = append(pairs[:step], append([]pair{{TileType_KEY, metals[i]}}, pairs[step:]...)...)
+	}
+	return append(pairs, pair{TileType_L6, TileType_L6})
+}
+
+// dealBoard draws a random symmetric layout, then a random order in which
+// its tiles can be removed, two free ones at a time with the middle one last.
+// The pairs of a random removal order are dealt on it, so the board holds
+// the solution it was built from. It fails when fewer than two tiles are
+// free before the end.
+func dealBoard(rng *rand.Rand) ([][]TileType, bool) {
+	pairs := removalOrder(rng)
+
+	// metals are only checked for neighbors while dealing
+	board := Board{AlchemyStage: AlchemyStage_5}
+	for _, i := range rng.Perm(len(rotationOrbits))[:(2*len(pairs)-2)/6] {
+		for _, pos := range rotationOrbits[i] {
+			board.Board[FromXYPos(pos.X, pos.Y)].Type = TileType_WHITE
+		}
+	}
+	middle := Position{nbLines / 2, nbLines / 2}
+	board.Board[FromXYPos(middle.X, middle.Y)].Type = TileType_WHITE
+
+	tiles := board.Tiles()
+	for _, p := range pairs[:len(pairs)-1] {
+		var free []Position
+		for i, tile := range board.Board {
+			x, y := ToXYPos(i)
+			if tile.Type != TileType_EMPTY && (Position{x, y}) != middle && !board.CheckLockState(x, y) {
+				free = append(free, Position{x, y})
+			}
+		}
+		if len(free) < 2 {
+			return nil, false
+		}
+
+		picked := rng.Perm(len(free))
+		pos1, pos2 := free[picked[0]], free[picked[1]]
+		tiles[pos1.X][pos1.Y] = p.type1
+		tiles[pos2.X][pos2.Y] = p.type2
+		board.Board[FromXYPos(pos1.X, pos1.Y)].Type = TileType_EMPTY
+		board.Board[FromXYPos(pos2.X, pos2.Y)].Type = TileType_EMPTY
+	}
+	tiles[middle.X][middle.Y] = pairs[len(pairs)-1].type1
+	return tiles, true
+}
@@ -0,0 +1,74 @@
+package sigmarsolver
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestGenerateSeed(t *testing.T) {
+	first, err := Generate(42, GenerateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _ := Generate(42, GenerateOptions{})
+	other, _ := Generate(43, GenerateOptions{})
+	if !reflect.DeepEqual(first.Tiles(), second.Tiles()) {
+		t.Error("seed 42 dealt two boards")
+	}
+	if reflect.DeepEqual(first.Tiles(), other.Tiles()) {
+		t.Error("seeds 42 and 43 dealt the same board")
+	}
+}
+
+// the standard inventory, on a layout symmetric by rotation with gold in
+// the middle, and a solution the rules accept
+func TestGenerateBoards(t *testing.T) {
+	want := map[TileType]int{
+		TileType_CYAN: 8, TileType_ORANGE: 8, TileType_BLUE: 8, TileType_GREEN: 8, TileType_WHITE: 4,
+		TileType_LIGHT: 4, TileType_DARK: 4, TileType_KEY: 5,
+		TileType_L1: 1, TileType_L2: 1, TileType_L3: 1, TileType_L4: 1, TileType_L5: 1, TileType_L6: 1,
+	}
+	for _, seed := range []int64{1, 2, 3, 4, 5, 6, 7, 8} {
+		name := fmt.Sprintf("seed %d", seed)
+		board, err := Generate(seed, GenerateOptions{})
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		count := map[TileType]int{}
+		for i, tile := range board.Board {
+			if tile.Type == TileType_EMPTY {
+				continue
+			}
+			count[tile.Type]++
+			x, y := ToXYPos(i)
+			rotated := rotatePosition(Position{x, y}, 1)
+			if board.Board[FromXYPos(rotated.X, rotated.Y)].Type == TileType_EMPTY {
+				t.Errorf("%s: (%d,%d) is not symmetric by rotation", name, x, y)
+			}
+		}
+		if !reflect.DeepEqual(count, want) {
+			t.Errorf("%s: inventory %v, want %v", name, count, want)
+		}
+		if middle := board.Board[FromXYPos(nbLines/2, nbLines/2)].Type; middle != TileType_L6 {
+			t.Errorf("%s: %s in the middle", name, middle)
+		}
+
+		solved := board.Clone()
+		actions, _, _, err := solved.SolveWithOptions(SolveOptions{Quiet: true})
+		if err != nil || len(actions) == 0 {
+			t.Fatalf("%s: not solved: %v", name, err)
+		}
+		checkSolution(t, name, board.Tiles(), actions)
+	}
+}
+
+func TestGenerateContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Generate(1, GenerateOptions{Context: ctx}); err != context.Canceled {
+		t.Errorf("canceled: %v", err)
+	}
+}
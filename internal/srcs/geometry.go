@@ -0,0 +1,45 @@
+package sigmarsolver
+
+import "math"
+
+// HexCenter returns the center of the tile (x, y) for hexagons of radius 1,
+// with the center of the board at (0, 0). Rows are horizontal: two tiles of
+// a row are sqrt(3) apart and two rows are 1.5 apart.
+func HexCenter(x, y int) (float64, float64) {
+	return (float64(y) - float64(lineSize[x]-1)/2) * math.Sqrt(3), float64(x-nbLines/2) * 1.5
+}
+
+// toAxial returns the axial coordinates (q, r) of the tile (x, y), the
+// middle tile being (0, 0) and r the row relative to the middle one.
+func toAxial(x, y int) (int, int) {
+	r := x - nbLines/2
+	if r < 0 {
+		return y - nbLines/2 - r, r
+	}
+	return y - nbLines/2, r
+}
+
+func fromAxial(q, r int) (int, int) {
+	if r < 0 {
+		return r + nbLines/2, q + nbLines/2 + r
+	}
+	return r + nbLines/2, q + nbLines/2
+}
+
+// rotatePosition rotates the position by n times 60 degrees clockwise around
+// the middle tile.
+func rotatePosition(pos Position, n int) Position {
+	q, r := toAxial(pos.X, pos.Y)
+	for i := 0; i < (n%6+6)%6; i++ {
+		q, r = -r, q+r
+	}
+	x, y := fromAxial(q, r)
+	return Position{x, y}
+}
+
+// mirrorPosition flips the position upside down, across the middle row.
+func mirrorPosition(pos Position) Position {
+	q, r := toAxial(pos.X, pos.Y)
+	x, y := fromAxial(q+r, -r)
+	return Position{x, y}
+}
@@ -0,0 +1,113 @@
+package sigmarsolver
+
+// Minimize removes pairs of tiles from the board while holds is true of
+// the tiles left, and returns the smallest board found. Only the pairs the
+// rules could remove together are tried, the last metal alone, and a
+// removal must keep the inventory well formed (see CheckInventory), so the
+// board left could still be a game board.
+//
+// The tiles are first paired the way a solution could remove them and the
+// pairs removed by delta debugging: halves of the pairs, then quarters and
+// so on. The removals left are then tried tile after tile, with each
+// partner of the tile, until a pass over the board removes nothing: no
+// single pair of the board returned can be removed.
+func Minimize(tiles [][]TileType, holds func(tiles [][]TileType) bool) [][]TileType {
+	board := NewBoard(tiles)
+	try := func(removed [][2]int) bool {
+		candidate := board.Tiles()
+		for _, pair := range removed {
+			for _, i := range pair {
+				x, y := ToXYPos(i)
+				candidate[x][y] = TileType_EMPTY
+			}
+		}
+		if len(CheckInventory(NewBoard(candidate))) > 0 || !holds(candidate) {
+			return false
+		}
+		board = NewBoard(candidate)
+		return true
+	}
+
+	pairs := pairTiles(board)
+	for n := 2; len(pairs) > 0; {
+		if n > len(pairs) {
+			n = len(pairs)
+		}
+		reduced := false
+		for i := 0; i < n && !reduced; i++ {
+			lo, hi := i*len(pairs)/n, (i+1)*len(pairs)/n
+			if reduced = try(pairs[lo:hi]); reduced {
+				pairs = append(pairs[:lo:lo], pairs[hi:]...)
+				if n > 2 {
+					n--
+				}
+			}
+		}
+		if !reduced {
+			if n == len(pairs) {
+				break
+			}
+			n *= 2
+		}
+	}
+
+	for removed := true; removed; {
+		removed = false
+		for i := range board.Board {
+			for j := i; j < len(board.Board) && board.Board[i].Type != TileType_EMPTY; j++ {
+				if canRemoveTogether(board, i, j) && try([][2]int{{i, j}}) {
+					removed = true
+				}
+			}
+		}
+	}
+	return board.Tiles()
+}
+
+// canRemoveTogether tells if the tiles i and j pair, whatever their lock,
+// i == j being the last metal alone.
+func canRemoveTogether(board Board, i, j int) bool {
+	type1, type2 := board.Board[i].Type, board.Board[j].Type
+	if i == j {
+		return type1 == TileType_L6
+	}
+	return type1 != TileType_L6 && CanPair(type1, type2)
+}
+
+// pairTiles pairs the tiles of the board, each one in one pair at most:
+// identical elements, light with dark, quicksilvers with the metals from the
+// last one, the elements left with salts and the salts together. The last
+// metal is paired with itself.
+func pairTiles(board Board) [][2]int {
+	indexes := map[TileType][]int{}
+	for i, tile := range board.Board {
+		indexes[tile.Type] = append(indexes[tile.Type], i)
+	}
+	var pairs [][2]int
+	pair := func(type1, type2 TileType) {
+		for len(indexes[type1]) > 0 && len(indexes[type2]) > 0 && (type1 != type2 || len(indexes[type1]) > 1) {
+			i := indexes[type1][0]
+			indexes[type1] = indexes[type1][1:]
+			j := indexes[type2][0]
+			indexes[type2] = indexes[type2][1:]
+			pairs = append(pairs, [2]int{i, j})
+		}
+	}
+
+	elements := []TileType{TileType_CYAN, TileType_ORANGE, TileType_BLUE, TileType_GREEN}
+	for _, element := range elements {
+		pair(element, element)
+	}
+	pair(TileType_LIGHT, TileType_DARK)
+	for _, i := range indexes[TileType_L6] {
+		pairs = append(pairs, [2]int{i, i})
+	}
+	for _, metal := range []TileType{TileType_L5, TileType_L4, TileType_L3, TileType_L2, TileType_L1} {
+		pair(TileType_KEY, metal)
+	}
+	for _, element := range elements {
+		pair(TileType_WHITE, element)
+	}
+	pair(TileType_WHITE, TileType_WHITE)
+	return pairs
+}
@@ -0,0 +1,38 @@
+package sigmarsolver
+
+import "testing"
+
+// keeping the last metal keeps the whole chain of metals, and nothing else
+func TestMinimizeKeepsWellFormed(t *testing.T) {
+	tiles := loadTiles(t, knownBoards[0])
+	minimized := Minimize(tiles, func(tiles [][]TileType) bool {
+		for _, line := range tiles {
+			for _, tile := range line {
+				if tile == TileType_L6 {
+					return true
+				}
+			}
+		}
+		return false
+	})
+
+	board := NewBoard(minimized)
+	if deadEnds := CheckInventory(board); len(deadEnds) > 0 {
+		t.Fatalf("minimized board is not well formed: %v", deadEnds)
+	}
+	count := map[TileType]int{}
+	for _, tile := range board.Board {
+		if tile.Type != TileType_EMPTY {
+			count[tile.Type]++
+		}
+	}
+	expected := map[TileType]int{TileType_KEY: 5, TileType_L1: 1, TileType_L2: 1, TileType_L3: 1, TileType_L4: 1, TileType_L5: 1, TileType_L6: 1}
+	for tileType, n := range expected {
+		if count[tileType] != n {
+			t.Errorf("%d %s left, expected %d", count[tileType], tileType, n)
+		}
+	}
+	if len(count) != len(expected) {
+		t.Errorf("tiles left: %v", count)
+	}
+}
@@ -0,0 +1,95 @@
+package sigmarsolver
+
+import (
+	"context"
+	"math"
+)
+
+type MoveSafety struct {
+	Action  Action
+	Outcome Outcome // Outcome_WIN if the board can still be solved after the move
+}
+
+// Oracle labels the legal moves of a board as safe or losing. The states it
+// proves solvable or not are remembered across calls, so following a game
+// move after move only searches the new states.
+type Oracle struct {
+	MaxChecks int64           // checks allowed to label one move, 0 for no limit
+	Context   context.Context // moves left are undecided once it is done, nil for no limit
+
+	wonStates  map[stateKey]bool
+	deadStates map[stateKey]bool
+}
+
+func NewOracle(maxChecks int64) *Oracle {
+	return &Oracle{
+		MaxChecks:  maxChecks,
+		wonStates:  map[stateKey]bool{},
+		deadStates: map[stateKey]bool{},
+	}
+}
+
+// Moves returns the pairs Solve would try from the board, in the same
+// order, each with its outcome. The board is left unchanged.
+func (this *Oracle) Moves(board Board) []MoveSafety {
+	maxChecks := this.budget()
+
+	board = board.Clone()
+	s := newSearch(&board)
+	var moves []MoveSafety
+	for _, move := range newIterator(&board, s.possibilities, Heuristic_ALCHEMY).foundPossibilities {
+		s.doAction(move.p1.X, move.p1.Y, move.p2.X, move.p2.Y)
+		action := s.actions[len(s.actions)-1]
+		budget := maxChecks
+		moves = append(moves, MoveSafety{Action: action, Outcome: this.outcome(s, &budget)})
+		s.undoLastAction()
+	}
+	return moves
+}
+
+// Solvable tells if the board can still be solved.
+func (this *Oracle) Solvable(board Board) Outcome {
+	maxChecks := this.budget()
+
+	board = board.Clone()
+	return this.outcome(newSearch(&board), &maxChecks)
+}
+
+// budget returns the checks allowed to label one move.
+func (this *Oracle) budget() int64 {
+	if this.MaxChecks <= 0 {
+		return math.MaxInt64
+	}
+	return this.MaxChecks
+}
+
+// outcome searches the current state of s depth first, spending the budget
+// one check per move tried. Only the proven states are remembered.
+func (this *Oracle) outcome(s *search, budget *int64) Outcome {
+	if s.remaining == 0 || this.wonStates[s.state] {
+		return Outcome_WIN
+	} else if this.deadStates[s.state] {
+		return Outcome_LOSS
+	}
+
+	state := s.state
+	for _, move := range newIterator(s.board, s.possibilities, Heuristic_ALCHEMY).foundPossibilities {
+		if *budget <= 0 || (this.Context != nil && *budget%1024 == 0 && this.Context.Err() != nil) {
+			return Outcome_UNKNOWN
+		}
+		*budget--
+
+		s.doAction(move.p1.X, move.p1.Y, move.p2.X, move.p2.Y)
+		outcome := this.outcome(s, budget)
+		s.undoLastAction()
+
+		if outcome == Outcome_WIN {
+			this.wonStates[state] = true
+			return Outcome_WIN
+		} else if outcome == Outcome_UNKNOWN {
+			return Outcome_UNKNOWN
+		}
+	}
+	this.deadStates[state] = true
+	return Outcome_LOSS
+}
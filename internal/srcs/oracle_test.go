@@ -0,0 +1,80 @@
+package sigmarsolver
+
+import (
+	"reflect"
+	"testing"
+)
+
+// two salts, one water and one fire: pairing the salts together leaves the
+// water and the fire, the other moves clear the board
+func saltsBoard() Board {
+	return NewBoard(tilesOf(map[Position]TileType{
+		{0, 0}:  TileType_WHITE,
+		{0, 5}:  TileType_WHITE,
+		{10, 0}: TileType_CYAN,
+		{10, 5}: TileType_ORANGE,
+	}))
+}
+
+// stateAfter returns the state of the board once the action is done
+func stateAfter(board Board, action Action) stateKey {
+	board = board.Clone()
+	s := newSearch(&board)
+	s.doAction(action.X1, action.Y1, action.X2, action.Y2)
+	return s.state
+}
+
+func TestOracleMoves(t *testing.T) {
+	board := saltsBoard()
+	oracle := NewOracle(0)
+	moves := oracle.Moves(board)
+	if len(moves) != 5 {
+		t.Fatalf("%d moves, want 5: %v", len(moves), moves)
+	}
+	for _, move := range moves {
+		losing := move.Action.Type1 == TileType_WHITE && move.Action.Type2 == TileType_WHITE
+		want := Outcome_WIN
+		if losing {
+			want = Outcome_LOSS
+		}
+		if move.Outcome != want {
+			t.Errorf("%s: %s, want %s", SolutionToString([]Action{move.Action}), move.Outcome, want)
+		}
+
+		// the states proven are remembered
+		state := stateAfter(board, move.Action)
+		if oracle.wonStates[state] == losing || oracle.deadStates[state] != losing {
+			t.Errorf("%s: won %v and dead %v", SolutionToString([]Action{move.Action}), oracle.wonStates[state], oracle.deadStates[state])
+		}
+	}
+	if !reflect.DeepEqual(board, saltsBoard()) {
+		t.Errorf("Moves changed the board")
+	}
+	if outcome := oracle.Solvable(board); outcome != Outcome_WIN {
+		t.Errorf("Solvable: %s", outcome)
+	}
+}
+
+func TestOracleSolvable(t *testing.T) {
+	lights := NewBoard(tilesOf(map[Position]TileType{{0, 0}: TileType_LIGHT, {10, 5}: TileType_LIGHT}))
+	if outcome := NewOracle(0).Solvable(lights); outcome != Outcome_LOSS {
+		t.Errorf("two lights: %s", outcome)
+	}
+	board := loadBoard(t, knownBoards[0])
+	if outcome := NewOracle(1).Solvable(board); outcome != Outcome_UNKNOWN {
+		t.Errorf("%s within one check: %s", knownBoards[0], outcome)
+	}
+}
+
+// once a state is proven, labeling it again takes no check
+func TestOracleCache(t *testing.T) {
+	board := loadBoard(t, knownBoards[0])
+	oracle := NewOracle(0)
+	if outcome := oracle.Solvable(board); outcome != Outcome_WIN {
+		t.Fatalf("%s: %s", knownBoards[0], outcome)
+	}
+	oracle.MaxChecks = 1
+	if outcome := oracle.Solvable(board); outcome != Outcome_WIN {
+		t.Errorf("%s once proven, within one check: %s", knownBoards[0], outcome)
+	}
+}
@@ -0,0 +1,164 @@
+package sigmarsolver
+
+import "math"
+
+type Outcome int
+
+const (
+	Outcome_UNKNOWN Outcome = iota // the search limit was reached
+	Outcome_WIN
+	Outcome_LOSS
+)
+
+func (this Outcome) String() string {
+	switch this {
+	case Outcome_WIN:
+		return "win"
+	case Outcome_LOSS:
+		return "loss"
+	default:
+		return "unknown"
+	}
+}
+
+// outcome tells if the board can still be solved from the current state,
+// which is left unchanged. The dead states found are remembered if the
+// search has deadStates.
+func (this *search) outcome(maxChecks int64) Outcome {
+	depth := len(this.actions)
+	err := this.run(SolveOptions{MaxChecks: maxChecks, Quiet: true}, nil)
+	solved := this.remaining == 0
+	this.undoTo(depth)
+
+	if err != nil {
+		return Outcome_UNKNOWN
+	} else if solved {
+		return Outcome_WIN
+	}
+	return Outcome_LOSS
+}
+
+type RateOptions struct {
+	MaxChecks    int64 // checks allowed to find a solution and to count them, 1000000 if 0
+	MoveChecks   int64 // checks allowed to tell if a move loses, 10000 if 0
+	MaxSolutions int   // solutions counted before stopping, 1000 if 0
+}
+
+type Rating struct {
+	Solvable        bool
+	Checks          int64   // checks of Solve to find the first solution
+	Solutions       int     // solutions counted, up to MaxSolutions
+	SolutionsExact  bool    // false if the count stopped at MaxSolutions or MaxChecks, Solutions being a lower bound
+	FirstMoves      int     // legal first moves
+	DeadFirstMoves  int     // first moves proven to lose
+	DeadEndRatio    float64 // DeadFirstMoves / FirstMoves
+	BranchingFactor float64 // average number of legal moves in the positions Solve went through, dead ends excepted
+	SolutionLength  int
+	FirstMistake    int // first step of the solution where a legal move loses, -1 if none was found
+	Score           float64
+	Level           string
+}
+
+// Rate measures how hard a board is from the effort of the search and the
+// number of moves that lose. The score goes from 0 to 100:
+//   - up to 25 for the checks needed by Solve, on a log scale,
+//   - up to 35 for the ratio of first moves that lose,
+//   - up to 20 for how early a move can lose along the solution,
+//   - up to 20 for how few solutions there are.
+//
+// Boards under 30 are easy, under 60 medium, hard above. Boards without a
+// solution found within MaxChecks get a score of 100 and are labeled
+// unsolvable, or unknown when the limit was reached.
+func Rate(board Board, opts RateOptions) Rating {
+	if opts.MaxChecks <= 0 {
+		opts.MaxChecks = 1000000
+	}
+	if opts.MoveChecks <= 0 {
+		opts.MoveChecks = 10000
+	}
+	if opts.MaxSolutions <= 0 {
+		opts.MaxSolutions = 1000
+	}
+
+	board = board.Clone()
+	s := newSearch(&board)
+	rating := Rating{FirstMistake: -1}
+
+	err := s.run(SolveOptions{MaxChecks: opts.MaxChecks, Quiet: true}, nil)
+	rating.Checks = s.n
+	if s.nodes > 0 {
+		rating.BranchingFactor = float64(s.branches) / float64(s.nodes)
+	}
+	solution := append([]Action(nil), s.actions...)
+	rating.Solvable = err == nil && s.remaining == 0
+	rating.SolutionLength = len(solution)
+	s.undoTo(0)
+	s.deadStates = map[stateKey]bool{}
+
+	if !rating.Solvable {
+		rating.Score = 100
+		rating.Level = "unsolvable"
+		if err != nil {
+			rating.Level = "unknown"
+		}
+		return rating
+	}
+
+	err = s.run(SolveOptions{MaxChecks: opts.MaxChecks, Quiet: true}, func() bool {
+		rating.Solutions++
+		return rating.Solutions < opts.MaxSolutions
+	})
+	rating.SolutionsExact = err == nil && rating.Solutions < opts.MaxSolutions
+
+	for depth, action := range solution {
+		moves := newIterator(&board, s.possibilities, Heuristic_ALCHEMY).foundPossibilities
+		if depth == 0 {
+			rating.FirstMoves = len(moves)
+		}
+		for _, move := range moves {
+			if rating.FirstMistake != -1 && depth > 0 {
+				break
+			}
+			s.doAction(move.p1.X, move.p1.Y, move.p2.X, move.p2.Y)
+			if s.outcome(opts.MoveChecks) == Outcome_LOSS {
+				if depth == 0 {
+					rating.DeadFirstMoves++
+				}
+				if rating.FirstMistake == -1 {
+					rating.FirstMistake = depth
+				}
+			}
+			s.undoLastAction()
+		}
+		if rating.FirstMistake != -1 {
+			break
+		}
+		s.doAction(action.X1, action.Y1, action.X2, action.Y2)
+	}
+	s.undoTo(0)
+
+	if rating.FirstMoves > 0 {
+		rating.DeadEndRatio = float64(rating.DeadFirstMoves) / float64(rating.FirstMoves)
+	}
+
+	minChecks := math.Max(1, float64(rating.SolutionLength))
+	rating.Score = 25 * math.Min(1, math.Log10(math.Max(1, float64(rating.Checks)/minChecks))/4)
+	rating.Score += 35 * rating.DeadEndRatio
+	if rating.FirstMistake != -1 {
+		rating.Score += 20 * (1 - float64(rating.FirstMistake)/minChecks)
+	}
+	rating.Score += 20 / (1 + math.Log10(math.Max(1, float64(rating.Solutions))))
+	rating.Level = levelOf(rating.Score)
+	return rating
+}
+
+func levelOf(score float64) string {
+	switch {
+	case score < 30:
+		return "easy"
+	case score < 60:
+		return "medium"
+	default:
+		return "hard"
+	}
+}
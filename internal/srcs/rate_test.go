@@ -0,0 +1,53 @@
+package sigmarsolver
+
+import (
+	"testing"
+)
+
+func TestRateSolvable(t *testing.T) {
+	for _, path := range []string{"../../inputs/input1.json", "../../inputs/input2.json", "../../inputs/input4.json"} {
+		rating := Rate(loadBoard(t, path), RateOptions{})
+		if !rating.Solvable || rating.Checks == 0 || rating.SolutionLength == 0 {
+			t.Errorf("%s: %+v", path, rating)
+			continue
+		}
+		if rating.Solutions < 1 || rating.FirstMoves == 0 || rating.DeadFirstMoves > rating.FirstMoves {
+			t.Errorf("%s: %+v", path, rating)
+		}
+		if rating.Score < 0 || rating.Score > 100 || rating.Level != levelOf(rating.Score) {
+			t.Errorf("%s: score %.1f level %s", path, rating.Score, rating.Level)
+		}
+	}
+}
+
+// either pair can be removed first; the solutions counted are a lower
+// bound when the count stops at MaxSolutions or at MaxChecks
+func TestRateSolutionsExact(t *testing.T) {
+	board := NewBoard(tilesOf(map[Position]TileType{
+		{0, 0}: TileType_CYAN, {0, 5}: TileType_CYAN, {10, 0}: TileType_ORANGE, {10, 5}: TileType_ORANGE,
+	}))
+	rating := Rate(board, RateOptions{})
+	if rating.Solutions != 2 || !rating.SolutionsExact {
+		t.Fatalf("two pairs: %d solutions, exact %v", rating.Solutions, rating.SolutionsExact)
+	}
+
+	if limited := Rate(board, RateOptions{MaxSolutions: 1}); limited.Solutions != 1 || limited.SolutionsExact {
+		t.Errorf("one solution at most: %d solutions, exact %v", limited.Solutions, limited.SolutionsExact)
+	}
+	limited := Rate(board, RateOptions{MaxChecks: rating.Checks})
+	if !limited.Solvable || limited.Solutions >= rating.Solutions || limited.SolutionsExact {
+		t.Errorf("%d checks: %d solutions of %d, exact %v", rating.Checks, limited.Solutions, rating.Solutions, limited.SolutionsExact)
+	}
+}
+
+func TestRateUnsolvable(t *testing.T) {
+	rating := Rate(loadBoard(t, "../../inputs/input_invalid.json"), RateOptions{MaxChecks: 10000})
+	if rating.Solvable || rating.Level != "unknown" || rating.Score != 100 {
+		t.Errorf("input_invalid: %+v", rating)
+	}
+
+	twoLights := NewBoard(tilesOf(map[Position]TileType{{0, 0}: TileType_LIGHT, {10, 5}: TileType_LIGHT}))
+	if rating := Rate(twoLights, RateOptions{}); rating.Solvable || rating.Level != "unsolvable" || rating.Score != 100 {
+		t.Errorf("two lights: %+v", rating)
+	}
+}
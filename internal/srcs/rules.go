@@ -0,0 +1,94 @@
+package sigmarsolver
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrIllegalMove = errors.New("illegal move")
+
+// CanPair tells if two tiles can be removed together, whatever their lock:
+// two identical elements, salt with an element or with salt, light with
+// dark and quicksilver with a metal. The last metal is removed alone, with
+// itself as second tile.
+func CanPair(type1, type2 TileType) bool {
+	if type1 == TileType_EMPTY || type2 == TileType_EMPTY {
+		return false
+	}
+	if isElement(type1) && type1 == type2 {
+		return true
+	}
+	if type1 == TileType_WHITE && (type2 == TileType_WHITE || isElement(type2)) ||
+		type2 == TileType_WHITE && isElement(type1) {
+		return true
+	}
+	if type1 == TileType_LIGHT && type2 == TileType_DARK || type1 == TileType_DARK && type2 == TileType_LIGHT {
+		return true
+	}
+	if type1 == TileType_KEY && isMetal(type2) || type2 == TileType_KEY && isMetal(type1) {
+		return true
+	}
+	return type1 == TileType_L6 && type2 == TileType_L6
+}
+
+func isElement(tileType TileType) bool {
+	switch tileType {
+	case TileType_CYAN, TileType_ORANGE, TileType_BLUE, TileType_GREEN:
+		return true
+	default:
+		return false
+	}
+}
+
+// isMetal tells if the tile is a metal paired with quicksilver.
+func isMetal(tileType TileType) bool {
+	stage := tileType.GetAlchemyStage()
+	return stage != AlchemyStage_0 && stage != AlchemyStage_FINAL
+}
+
+// CheckMove tells why the pair (x1,y1) (x2,y2) cannot be removed from the
+// board, nil if it can. The same position twice is the last metal removed
+// alone.
+func (this *Board) CheckMove(x1, y1, x2, y2 int) error {
+	for _, pos := range []Position{{x1, y1}, {x2, y2}} {
+		if !IsPossitionValid(pos.X, pos.Y) {
+			return fmt.Errorf("%w: (%d,%d) is out of the board", ErrIllegalMove, pos.X, pos.Y)
+		}
+		tile := this.Board[FromXYPos(pos.X, pos.Y)]
+		if tile.Type == TileType_EMPTY {
+			return fmt.Errorf("%w: (%d,%d) is empty", ErrIllegalMove, pos.X, pos.Y)
+		}
+		if tile.Lock {
+			return fmt.Errorf("%w: %s (%d,%d) is locked", ErrIllegalMove, tile.Type, pos.X, pos.Y)
+		}
+		// only boards with a metal twice have a free metal out of order
+		if stage := tile.Type.GetAlchemyStage(); stage != AlchemyStage_0 && stage != this.AlchemyStage+1 {
+			return fmt.Errorf("%w: %s (%d,%d) is not the next metal", ErrIllegalMove, tile.Type, pos.X, pos.Y)
+		}
+	}
+
+	type1, type2 := this.Board[FromXYPos(x1, y1)].Type, this.Board[FromXYPos(x2, y2)].Type
+	if (x1 == x2 && y1 == y2) != (type1 == TileType_L6) {
+		if type1 == TileType_L6 {
+			return fmt.Errorf("%w: %s is removed alone", ErrIllegalMove, type1)
+		}
+		return fmt.Errorf("%w: %s needs a partner", ErrIllegalMove, type1)
+	}
+	if !CanPair(type1, type2) {
+		return fmt.Errorf("%w: %s does not pair with %s", ErrIllegalMove, type1, type2)
+	}
+	return nil
+}
+
+// Move removes the pair (x1,y1) (x2,y2) if it is legal, in any order, and
+// returns the action to give back to UndoAction.
+func (this *Board) Move(x1, y1, x2, y2 int) (Action, error) {
+	if err := this.CheckMove(x1, y1, x2, y2); err != nil {
+		return Action{}, err
+	}
+	// ApplyAction expects the salt first
+	if this.Board[FromXYPos(x2, y2)].Type == TileType_WHITE {
+		x1, y1, x2, y2 = x2, y2, x1, y1
+	}
+	return this.ApplyAction(x1, y1, x2, y2), nil
+}
@@ -0,0 +1,264 @@
+package sigmarsolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"sigmars-garden-solver/internal/sat"
+)
+
+// Backend is the algorithm searching the solution.
+type Backend string
+
+const (
+	Backend_DFS Backend = "dfs" // depth first search over the pairs, ordered by the heuristic
+	Backend_SAT Backend = "sat" // SAT formula of the whole game, checks are its conflicts
+)
+
+var Backends = []Backend{Backend_DFS, Backend_SAT}
+
+func (this Backend) IsValid() bool {
+	for _, backend := range Backends {
+		if this == backend {
+			return true
+		}
+	}
+	return false
+}
+
+// satEncoding is the game as a SAT formula over steps 0 to steps-1, each
+// removing a pair of tiles or the gold alone:
+//   - empty[i][t] tells the tile i was removed before the step t, it stays
+//     removed and all tiles are removed after the last step;
+//   - pair[k][t] tells the pair k is removed at the step t, exactly one pair
+//     is, and each tile removed at a step is removed by its pair;
+//   - a tile removed at a step has three neighbors in a row empty before it,
+//     the tiles out of the board and those empty from the start being;
+//   - a metal removed at a step has the metals before it empty.
+type satEncoding struct {
+	solver *sat.Solver
+	tiles  []int    // positions of the tiles on the board
+	pairs  [][2]int // indexes in tiles, twice the gold alone
+	empty  [][]sat.Lit
+	pair   [][]sat.Lit
+}
+
+// metalsInOrder tells if the metals of the board can be removed, each one
+// being the next metal once the previous one is.
+func (this *Board) metalsInOrder() bool {
+	var stages []int
+	for _, tile := range this.Board {
+		if stage := tile.Type.GetAlchemyStage(); stage != AlchemyStage_0 {
+			stages = append(stages, int(stage))
+		}
+	}
+	sort.Ints(stages)
+	for i, stage := range stages {
+		if stage != int(this.AlchemyStage)+i+1 {
+			return false
+		}
+	}
+	return true
+}
+
+// newSATEncoding returns the formula of the board, nil if the board
+// obviously has no solution: metals out of order, a tile left without
+// partner or no pair at all.
+func newSATEncoding(board *Board) *satEncoding {
+	this := &satEncoding{solver: sat.New()}
+	index := map[int]int{}
+	gold := 0
+	for i, tile := range board.Board {
+		if tile.Type != TileType_EMPTY {
+			index[i] = len(this.tiles)
+			this.tiles = append(this.tiles, i)
+			if tile.Type == TileType_L6 {
+				gold++
+			}
+		}
+	}
+	if !board.metalsInOrder() || (len(this.tiles)-gold)%2 == 1 {
+		return nil
+	}
+	steps := (len(this.tiles)-gold)/2 + gold
+
+	typeOf := func(k int) TileType {
+		return board.Board[this.tiles[k]].Type
+	}
+	for a := range this.tiles {
+		if typeOf(a) == TileType_L6 {
+			this.pairs = append(this.pairs, [2]int{a, a})
+			continue
+		}
+		for b := a + 1; b < len(this.tiles); b++ {
+			if typeOf(b) != TileType_L6 && CanPair(typeOf(a), typeOf(b)) {
+				this.pairs = append(this.pairs, [2]int{a, b})
+			}
+		}
+	}
+
+	if len(this.pairs) == 0 {
+		return nil
+	}
+
+	s := this.solver
+	this.empty = make([][]sat.Lit, len(this.tiles))
+	for a := range this.tiles {
+		for t := 0; t <= steps; t++ {
+			this.empty[a] = append(this.empty[a], s.NewVar())
+		}
+		s.AddClause(-this.empty[a][0])
+		s.AddClause(this.empty[a][steps])
+		for t := 0; t < steps; t++ {
+			s.AddClause(-this.empty[a][t], this.empty[a][t+1])
+		}
+	}
+
+	this.pair = make([][]sat.Lit, len(this.pairs))
+	pairsOf := make([][]int, len(this.tiles))
+	for k, p := range this.pairs {
+		for t := 0; t < steps; t++ {
+			this.pair[k] = append(this.pair[k], s.NewVar())
+		}
+		pairsOf[p[0]] = append(pairsOf[p[0]], k)
+		if p[1] != p[0] {
+			pairsOf[p[1]] = append(pairsOf[p[1]], k)
+		}
+	}
+	for t := 0; t < steps; t++ {
+		var step []sat.Lit
+		for k, p := range this.pairs {
+			step = append(step, this.pair[k][t])
+			for _, a := range p {
+				s.AddClause(-this.pair[k][t], -this.empty[a][t])
+				s.AddClause(-this.pair[k][t], this.empty[a][t+1])
+			}
+		}
+		s.AddClause(step...)
+		this.atMostOne(step)
+
+		for a := range this.tiles {
+			clause := []sat.Lit{-this.empty[a][t+1], this.empty[a][t]}
+			for _, k := range pairsOf[a] {
+				clause = append(clause, this.pair[k][t])
+			}
+			s.AddClause(clause...)
+		}
+	}
+
+	for a, pos := range this.tiles {
+		x, y := ToXYPos(pos)
+		neighbors := getAllPossibleJoinedTiles(x, y)
+		var rows [][]sat.Lit
+		free := false
+		for r := 0; r < 6 && !free; r++ {
+			var row []int
+			for _, n := range []Position{neighbors[r], neighbors[(r+1)%6], neighbors[(r+2)%6]} {
+				if IsPossitionValid(n.X, n.Y) && board.Board[FromXYPos(n.X, n.Y)].Type != TileType_EMPTY {
+					row = append(row, index[FromXYPos(n.X, n.Y)])
+				}
+			}
+			if len(row) == 0 {
+				free = true
+			}
+			rows = append(rows, nil)
+			for t := 0; t < steps && !free; t++ {
+				// the row is empty before the step t
+				lit := s.NewVar()
+				for _, b := range row {
+					s.AddClause(-lit, this.empty[b][t])
+				}
+				rows[r] = append(rows[r], lit)
+			}
+		}
+		if free {
+			continue
+		}
+		for t := 0; t < steps; t++ {
+			clause := []sat.Lit{-this.empty[a][t+1], this.empty[a][t]}
+			for r := range rows {
+				clause = append(clause, rows[r][t])
+			}
+			s.AddClause(clause...)
+		}
+	}
+
+	for a := range this.tiles {
+		stage := typeOf(a).GetAlchemyStage()
+		if stage == AlchemyStage_0 {
+			continue
+		}
+		for b := range this.tiles {
+			if other := typeOf(b).GetAlchemyStage(); other != AlchemyStage_0 && other < stage {
+				for t := 0; t < steps; t++ {
+					s.AddClause(-this.empty[a][t+1], this.empty[a][t], this.empty[b][t])
+				}
+			}
+		}
+	}
+	return this
+}
+
+// atMostOne adds the sequential counter of the literals: counted[i] tells
+// one of the first i+1 literals is true.
+func (this *satEncoding) atMostOne(lits []sat.Lit) {
+	if len(lits) <= 1 {
+		return
+	}
+	s := this.solver
+	counted := make([]sat.Lit, len(lits)-1)
+	for i := range counted {
+		counted[i] = s.NewVar()
+		s.AddClause(-lits[i], counted[i])
+		if i > 0 {
+			s.AddClause(-counted[i-1], counted[i])
+			s.AddClause(-lits[i], -counted[i-1])
+		}
+	}
+	s.AddClause(-lits[len(lits)-1], -counted[len(lits)-2])
+}
+
+// solveSAT is SolveWithOptions with the SAT backend. The moves found are
+// played on the board, as the depth first search does.
+func (this *Board) solveSAT(opts SolveOptions) ([]Action, int64, time.Duration, error) {
+	start := time.Now()
+	encoding := newSATEncoding(this)
+	if encoding == nil || len(encoding.tiles) == 0 {
+		return nil, 0, time.Since(start), nil
+	}
+
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	solved, err := encoding.solver.Solve(ctx, opts.MaxChecks)
+	n := encoding.solver.Conflicts()
+	if errors.Is(err, sat.ErrConflictLimit) {
+		err = ErrSearchLimit
+	}
+	if err != nil || !solved {
+		return nil, n, time.Since(start), err
+	}
+
+	board := this.Clone()
+	var actions []Action
+	for t := range encoding.pair[0] {
+		for k, p := range encoding.pairs {
+			if !encoding.solver.Value(encoding.pair[k][t]) {
+				continue
+			}
+			x1, y1 := ToXYPos(encoding.tiles[p[0]])
+			x2, y2 := ToXYPos(encoding.tiles[p[1]])
+			action, err := board.Move(x1, y1, x2, y2)
+			if err != nil {
+				panic(fmt.Sprintf("sat: step %d: %v", t, err))
+			}
+			actions = append(actions, action)
+		}
+	}
+	*this = board
+	return actions, n, time.Since(start), nil
+}
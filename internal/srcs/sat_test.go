@@ -0,0 +1,119 @@
+package sigmarsolver
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSATSolutionsVerify(t *testing.T) {
+	for _, path := range knownBoards {
+		tiles := loadTiles(t, path)
+		board := NewBoard(tiles)
+		actions, _, _, err := board.SolveWithOptions(SolveOptions{Backend: Backend_SAT})
+		if err != nil || len(actions) == 0 {
+			t.Fatalf("%s: no solution: %v", path, err)
+		}
+		checkSolution(t, path, tiles, actions)
+	}
+}
+
+// smallBoard keeps a few pairs of a generated board, swapping two of its
+// tiles once in a while so some have no solution.
+func smallBoard(rng *rand.Rand, board Board) [][]TileType {
+	pairs := pairTiles(board)
+	rng.Shuffle(len(pairs), func(i, j int) { pairs[i], pairs[j] = pairs[j], pairs[i] })
+	var kept []int
+	for _, pair := range pairs[3+rng.Intn(7):] {
+		board.Board[pair[0]].Type = TileType_EMPTY
+		board.Board[pair[1]].Type = TileType_EMPTY
+	}
+	for i, tile := range board.Board {
+		if tile.Type != TileType_EMPTY {
+			kept = append(kept, i)
+		}
+	}
+	if rng.Intn(2) == 0 {
+		i, j := kept[rng.Intn(len(kept))], kept[rng.Intn(len(kept))]
+		board.Board[i].Type, board.Board[j].Type = board.Board[j].Type, board.Board[i].Type
+	}
+	return board.Tiles()
+}
+
+// the backends agree on small boards, which the depth first search covers
+// in full
+func TestSATAgreesWithDFS(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	var board Board
+	solved := 0
+	for i := 0; i < 300; i++ {
+		if i%50 == 0 {
+			generated, err := Generate(int64(i), GenerateOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			board = generated
+		}
+		tiles := smallBoard(rng, board)
+		dfs, sat := NewBoard(tiles), NewBoard(tiles)
+		want, _, _, err := dfs.SolveWithOptions(SolveOptions{Quiet: true, Memo: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		actions, _, _, err := sat.SolveWithOptions(SolveOptions{Backend: Backend_SAT})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if (len(actions) > 0) != (len(want) > 0) {
+			t.Fatalf("%s\nsat found %d moves, dfs %d", tilesString(tiles), len(actions), len(want))
+		}
+		if len(actions) > 0 {
+			checkSolution(t, "small board", tiles, actions)
+			solved++
+		}
+	}
+	t.Logf("%d boards solved out of 300", solved)
+}
+
+// boards ParseBoard accepts but without a legal pair, or a single one
+func TestSATFewPairs(t *testing.T) {
+	tests := []struct {
+		name   string
+		tiles  map[Position]TileType
+		solved bool
+	}{
+		{"two lights", map[Position]TileType{{0, 0}: TileType_LIGHT, {10, 5}: TileType_LIGHT}, false},
+		{"light and dark", map[Position]TileType{{0, 0}: TileType_LIGHT, {10, 5}: TileType_DARK}, true},
+		{"gold", map[Position]TileType{{5, 5}: TileType_L6}, false},
+	}
+	for _, test := range tests {
+		tiles := make([][]TileType, nbLines)
+		for x := range tiles {
+			tiles[x] = make([]TileType, lineSize[x])
+		}
+		for pos, tileType := range test.tiles {
+			tiles[pos.X][pos.Y] = tileType
+		}
+		board, err := ParseBoard(tiles)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		dfs := board.Clone()
+		want, _, _, _ := dfs.SolveWithOptions(SolveOptions{Quiet: true})
+		actions, _, _, err := board.SolveWithOptions(SolveOptions{Backend: Backend_SAT})
+		if err != nil || (len(actions) > 0) != test.solved || (len(want) > 0) != test.solved {
+			t.Errorf("%s: sat found %d moves, dfs %d: %v", test.name, len(actions), len(want), err)
+		}
+	}
+}
+
+func TestSATLimits(t *testing.T) {
+	board := loadBoard(t, "../../inputs/input1.json")
+	before := board.Clone()
+	actions, n, _, err := board.SolveWithOptions(SolveOptions{Backend: Backend_SAT, MaxChecks: 10})
+	if err != ErrSearchLimit || len(actions) != 0 || n != 10 {
+		t.Errorf("%d moves after %d checks: %v, want %v after 10", len(actions), n, err, ErrSearchLimit)
+	}
+	if board.AlchemyStage != before.AlchemyStage || board.Board != before.Board {
+		t.Error("the stopped search changed the board")
+	}
+}
@@ -1,9 +1,12 @@
 package sigmarsolver
 
 import (
+	"errors"
 	"fmt"
 )
 
+var ErrInvalidBoard = errors.New("invalid board")
+
 func NewBoard(tiles [][]TileType) Board {
 	board := Board{
 		TileTypesRemainingMap: make(map[TileType]int),
@@ -34,6 +37,48 @@ func NewBoard(tiles [][]TileType) Board {
 	return board
 }
 
+// ParseBoard is NewBoard returning an error, instead of panicking, when the
+// tiles do not have the shape of the board or are of an unknown type.
+func ParseBoard(tiles [][]TileType) (Board, error) {
+	if len(tiles) != nbLines {
+		return Board{}, fmt.Errorf("%w: wrong number of lines (expected %d got %d)", ErrInvalidBoard, nbLines, len(tiles))
+	}
+	for x, line := range tiles {
+		if len(line) != lineSize[x] {
+			return Board{}, fmt.Errorf("%w: line %d: wrong line length (expected %d got %d)", ErrInvalidBoard, x, lineSize[x], len(line))
+		}
+		for y, tile := range line {
+			if !tile.IsValid() {
+				return Board{}, fmt.Errorf("%w: (%d,%d): unknown tile %q", ErrInvalidBoard, x, y, tile)
+			}
+		}
+	}
+	return NewBoard(tiles), nil
+}
+
+// Clone returns a deep copy of the board, so it can be played or solved
+// without altering the original.
+func (this Board) Clone() Board {
+	clone := this
+	clone.TileTypesRemainingMap = make(map[TileType]int, len(this.TileTypesRemainingMap))
+	for k, v := range this.TileTypesRemainingMap {
+		clone.TileTypesRemainingMap[k] = v
+	}
+	return clone
+}
+
+// Tiles returns the tile types of the board, in the format NewBoard accepts.
+func (this Board) Tiles() [][]TileType {
+	tiles := make([][]TileType, nbLines)
+	for x := range tiles {
+		tiles[x] = make([]TileType, lineSize[x])
+		for y := range tiles[x] {
+			tiles[x][y] = this.Board[FromXYPos(x, y)].Type
+		}
+	}
+	return tiles
+}
+
 func (this *Board) CheckLockState(x, y int) bool {
 	// Check empty
 	if this.Board[FromXYPos(x, y)].Type == TileType_EMPTY {
@@ -138,17 +183,22 @@ func IsPossitionValid(x, y int) bool {
 }
 
 func getAllPossibleJoinedTiles(x, y int) [6]Position {
-	isUpper := 0
-	if x >= nbLines/2 {
-		isUpper = 1
+	// rows shrink away from the middle one, whose neighbors are shifted on
+	// both sides
+	up, down := 0, 0
+	if x > nbLines/2 {
+		up = 1
+	}
+	if x < nbLines/2 {
+		down = 1
 	}
 
 	return [6]Position{
-		{x - 1, y - 1 + isUpper},
-		{x - 1, y + isUpper},
+		{x - 1, y - 1 + up},
+		{x - 1, y + up},
 		{x, y + 1},
-		{x + 1, y + 1 - isUpper},
-		{x + 1, y - isUpper},
+		{x + 1, y + down},
+		{x + 1, y - 1 + down},
 		{x, y - 1},
 	}
 }
@@ -0,0 +1,276 @@
+package sigmarsolver
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files of testdata/")
+
+// boards known to be solvable, shared by the tests
+var knownBoards = []string{
+	"../../inputs/input1.json",
+	"../../inputs/input2.json",
+	"../../inputs/input3.json",
+	"../../inputs/input4.json",
+}
+
+func loadTiles(t testing.TB, path string) [][]TileType {
+	t.Helper()
+	byteValue, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tiles [][]TileType
+	if err := json.Unmarshal(byteValue, &tiles); err != nil {
+		t.Fatalf("%s: %v", path, err)
+	}
+	return tiles
+}
+
+func loadBoard(t testing.TB, path string) Board {
+	t.Helper()
+	board, err := ParseBoard(loadTiles(t, path))
+	if err != nil {
+		t.Fatalf("%s: %v", path, err)
+	}
+	return board
+}
+
+func TestPositionRoundTrip(t *testing.T) {
+	for i := 0; i < startLineValue[nbLines]; i++ {
+		x, y := ToXYPos(i)
+		if !IsPossitionValid(x, y) {
+			t.Errorf("ToXYPos(%d) = (%d,%d) is out of the board", i, x, y)
+		}
+		if got := FromXYPos(x, y); got != i {
+			t.Errorf("FromXYPos(ToXYPos(%d)) = %d", i, got)
+		}
+	}
+	n := 0
+	for x := 0; x < nbLines; x++ {
+		for y := 0; y < lineSize[x]; y++ {
+			if gx, gy := ToXYPos(FromXYPos(x, y)); gx != x || gy != y {
+				t.Errorf("ToXYPos(FromXYPos(%d,%d)) = (%d,%d)", x, y, gx, gy)
+			}
+			n++
+		}
+	}
+	if n != 91 {
+		t.Errorf("%d positions, expected 91", n)
+	}
+}
+
+func hexDistance(p1, p2 Position) int {
+	q1, r1 := toAxial(p1.X, p1.Y)
+	q2, r2 := toAxial(p2.X, p2.Y)
+	abs := func(a int) int {
+		if a < 0 {
+			return -a
+		}
+		return a
+	}
+	return (abs(q1-q2) + abs(r1-r2) + abs(q1+r1-q2-r2)) / 2
+}
+
+func TestNeighborsSymmetric(t *testing.T) {
+	for i := 0; i < startLineValue[nbLines]; i++ {
+		x, y := ToXYPos(i)
+		pos := Position{x, y}
+		for dir, neighbor := range getAllPossibleJoinedTiles(x, y) {
+			// neighbors are given clockwise, the opposite direction three
+			// steps further
+			if back := getAllPossibleJoinedTiles(neighbor.X, neighbor.Y)[(dir+3)%6]; back != pos {
+				t.Errorf("neighbor %d of %v is %v, whose neighbor %d is %v", dir, pos, neighbor, (dir+3)%6, back)
+			}
+			if IsPossitionValid(neighbor.X, neighbor.Y) && hexDistance(pos, neighbor) != 1 {
+				t.Errorf("neighbor %d of %v is %v, at distance %d", dir, pos, neighbor, hexDistance(pos, neighbor))
+			}
+		}
+
+		var expected []Position
+		for j := 0; j < startLineValue[nbLines]; j++ {
+			x2, y2 := ToXYPos(j)
+			if hexDistance(pos, Position{x2, y2}) == 1 {
+				expected = append(expected, Position{x2, y2})
+			}
+		}
+		joined := getAllJoinedTiles(x, y)
+		sortPositions(joined)
+		if !reflect.DeepEqual(joined, expected) {
+			t.Errorf("neighbors of %v: got %v expected %v", pos, joined, expected)
+		}
+	}
+}
+
+// regression cases of the rows around the middle one, where the upper and
+// the lower halves of the board meet
+func TestNeighborsMiddleRows(t *testing.T) {
+	tests := []struct {
+		pos      Position
+		expected [6]Position
+	}{
+		{Position{5, 0}, [6]Position{{4, -1}, {4, 0}, {5, 1}, {6, 0}, {6, -1}, {5, -1}}},
+		{Position{5, 5}, [6]Position{{4, 4}, {4, 5}, {5, 6}, {6, 5}, {6, 4}, {5, 4}}},
+		{Position{5, 10}, [6]Position{{4, 9}, {4, 10}, {5, 11}, {6, 10}, {6, 9}, {5, 9}}},
+		{Position{4, 0}, [6]Position{{3, -1}, {3, 0}, {4, 1}, {5, 1}, {5, 0}, {4, -1}}},
+		{Position{4, 9}, [6]Position{{3, 8}, {3, 9}, {4, 10}, {5, 10}, {5, 9}, {4, 8}}},
+		{Position{6, 0}, [6]Position{{5, 0}, {5, 1}, {6, 1}, {7, 0}, {7, -1}, {6, -1}}},
+		{Position{6, 9}, [6]Position{{5, 9}, {5, 10}, {6, 10}, {7, 9}, {7, 8}, {6, 8}}},
+		{Position{0, 0}, [6]Position{{-1, -1}, {-1, 0}, {0, 1}, {1, 1}, {1, 0}, {0, -1}}},
+		{Position{10, 5}, [6]Position{{9, 5}, {9, 6}, {10, 6}, {11, 5}, {11, 4}, {10, 4}}},
+	}
+	for _, test := range tests {
+		if got := getAllPossibleJoinedTiles(test.pos.X, test.pos.Y); got != test.expected {
+			t.Errorf("neighbors of %v: got %v expected %v", test.pos, got, test.expected)
+		}
+	}
+}
+
+// (5,5) has its upper left, upper right and left neighbors empty: it is
+// free, which the upper neighbors of the row below, (4,5) and (4,6), hid
+func TestMiddleRowLock(t *testing.T) {
+	tiles := make([][]TileType, nbLines)
+	for x := range tiles {
+		tiles[x] = make([]TileType, lineSize[x])
+	}
+	tiles[5][5] = TileType_BLUE
+	for _, pos := range []Position{{5, 6}, {6, 5}, {6, 4}, {4, 6}} {
+		tiles[pos.X][pos.Y] = TileType_WHITE
+	}
+	board := NewBoard(tiles)
+	if board.Board[FromXYPos(5, 5)].Lock {
+		t.Error("(5,5) is locked")
+	}
+}
+
+// distances are counted along rays leaving the metal and a later ray may
+// overwrite a shorter distance: they are never shorter than the distance on
+// the board, and 0 only on the metal itself
+func TestAlchemyDistances(t *testing.T) {
+	for _, path := range knownBoards {
+		board := loadBoard(t, path)
+		for i, tile := range board.Board {
+			stage := tile.Type.GetAlchemyStage()
+			if stage == AlchemyStage_0 {
+				continue
+			}
+			metal := Position{}
+			metal.X, metal.Y = ToXYPos(i)
+			for j, other := range board.Board {
+				x, y := ToXYPos(j)
+				d, min := other.DistanceToAlchs[stage-1], hexDistance(metal, Position{x, y})
+				if d < min || (d == 0) != (i == j) {
+					t.Errorf("%s: (%d,%d) is at distance %d of %s %v, %d on the board", path, x, y, d, tile.Type, metal, min)
+				}
+			}
+		}
+	}
+}
+
+// locksString draws the board, the types of the unlocked tiles in upper
+// case and of the locked ones in lower case.
+func locksString(board Board) string {
+	var b strings.Builder
+	for x := 0; x < nbLines; x++ {
+		b.WriteString(strings.Repeat("  ", nbLines-lineSize[x]))
+		for y := 0; y < lineSize[x]; y++ {
+			tile := board.Board[FromXYPos(x, y)]
+			code := ".."
+			if tile.Type != TileType_EMPTY {
+				code = string(tile.Type[:2])
+				if !tile.Lock {
+					code = strings.ToUpper(code)
+				}
+			}
+			b.WriteString(code + "  ")
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// distancesString draws the distances of the tiles to the metal of the
+// stage.
+func distancesString(board Board, stage AlchemyStage) string {
+	var b strings.Builder
+	for x := 0; x < nbLines; x++ {
+		b.WriteString(strings.Repeat("  ", nbLines-lineSize[x]))
+		for y := 0; y < lineSize[x]; y++ {
+			fmt.Fprintf(&b, "%2d  ", board.Board[FromXYPos(x, y)].DistanceToAlchs[stage])
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// checkGolden compares got with the golden file, rewritten with -update.
+func checkGolden(t *testing.T, path, got string) {
+	t.Helper()
+	if *update {
+		if err := ioutil.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	byteValue, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (go test -run %s -update writes it)", err, t.Name())
+	}
+	if string(byteValue) != got {
+		t.Errorf("%s differs, got:\n%s", path, got)
+	}
+}
+
+// TestLockGolden pins the locks of the known boards at the start and along
+// their solution, and the distances of the tiles to the first metal.
+func TestLockGolden(t *testing.T) {
+	for _, path := range knownBoards {
+		name := strings.TrimSuffix(filepath.Base(path), ".json")
+		t.Run(name, func(t *testing.T) {
+			board := loadBoard(t, path)
+			solved := board.Clone()
+			actions, _, _, err := solved.SolveWithOptions(SolveOptions{Quiet: true, Memo: true})
+			if err != nil || len(actions) == 0 {
+				t.Fatalf("no solution: %v", err)
+			}
+
+			var b strings.Builder
+			fmt.Fprintf(&b, "distances to %s\n%s\n", TileType_L1, distancesString(board, AlchemyStage_0))
+			fmt.Fprintf(&b, "start\n%s", locksString(board))
+			for i, action := range actions {
+				if _, err := board.Move(action.X1, action.Y1, action.X2, action.Y2); err != nil {
+					t.Fatalf("move %d: %v", i+1, err)
+				}
+				if (i+1)%(len(actions)/4) == 0 {
+					fmt.Fprintf(&b, "\nafter move %d: %s", i+1, SolutionToString([]Action{action}))
+					b.WriteString(locksString(board))
+				}
+			}
+			checkGolden(t, filepath.Join("testdata", "locks", name+".golden"), b.String())
+		})
+	}
+}
+
+// the locks kept up to date move after move are the locks computed from
+// scratch
+func TestLocksAfterMoves(t *testing.T) {
+	for _, path := range knownBoards {
+		randomWalks(t, loadBoard(t, path), 20, func(board *Board, actions []Action) {
+			fresh := board.Clone()
+			for i := range fresh.Board {
+				x, y := ToXYPos(i)
+				fresh.CheckLockState(x, y)
+			}
+			if fresh.Board != board.Board {
+				t.Fatalf("%s: after %s locks are\n%sinstead of\n%s", path, SolutionToString(actions), locksString(*board), locksString(fresh))
+			}
+		})
+	}
+}
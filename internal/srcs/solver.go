@@ -0,0 +1,501 @@
+package sigmarsolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+type Action struct {
+	X1       int        `json:"x1"`
+	Y1       int        `json:"y1"`
+	X2       int        `json:"x2"`
+	Y2       int        `json:"y2"`
+	Type1    TileType   `json:"type1"`
+	Type2    TileType   `json:"type2"`
+	Unlocked []Position `json:"-"`
+}
+
+type Possibilities map[TileType][]Position
+
+func (this Possibilities) Insert(tileType TileType, pos Position) {
+	this[tileType] = append(this[tileType], pos)
+}
+
+func (this Possibilities) Remove(pos Position) {
+	for k, v := range this {
+		for i, p := range v {
+			if p == pos {
+				this[k][i] = this[k][len(v)-1]
+				this[k] = this[k][:len(v)-1]
+				return
+			}
+		}
+	}
+	panic(fmt.Sprintf("pos %v not found in possibilities", pos))
+}
+
+func (this Possibilities) Sort() {
+	for k, v := range this {
+		sort.Slice(this[k], func(i, j int) bool { return v[i].X < v[j].X || (v[i].X == v[j].X && v[i].Y < v[j].Y) })
+	}
+}
+
+func (this Possibilities) IsEmpty() bool {
+	for _, arr := range this {
+		if len(arr) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+type SolveOptions struct {
+	MaxChecks int64           // give up after that many checks, 0 for no limit
+	Quiet     bool            // do not print the progress every 100000 checks
+	Context   context.Context // give up when it is done, nil for no limit
+	Heuristic Heuristic       // order of the pairs tried, Heuristic_ALCHEMY if empty
+	Memo      bool            // remember the states without solution, not to search them twice
+	Backend   Backend         // Backend_DFS if empty, the other options being for it
+
+	// called every 10000 checks with the state of the search, nil for none
+	OnProgress func(Progress)
+}
+
+type Progress struct {
+	Checks    int64
+	Depth     int // number of actions played
+	Remaining int // tiles left on the board
+}
+
+var ErrSearchLimit = errors.New("search limit reached")
+
+// Heuristic orders the pairs tried from a state of the search.
+type Heuristic string
+
+const (
+	Heuristic_ALCHEMY Heuristic = "alchemy" // pairs closest to the next metal first
+	Heuristic_NONE    Heuristic = "none"    // pairs in the order of the board
+)
+
+var Heuristics = []Heuristic{Heuristic_ALCHEMY, Heuristic_NONE}
+
+func (this Heuristic) IsValid() bool {
+	return this == "" || this == Heuristic_ALCHEMY || this == Heuristic_NONE
+}
+
+// Solve searches a solution, printing its progress on long searches. The
+// returned actions are empty if the board has no solution.
+func (this *Board) Solve() ([]Action, int64, time.Duration) {
+	actions, n, dur, _ := this.SolveWithOptions(SolveOptions{})
+	return actions, n, dur
+}
+
+// SolveWithOptions is Solve with a bounded search. When the search is
+// stopped, the board is restored and the error tells why: ErrSearchLimit or
+// the error of the context.
+func (this *Board) SolveWithOptions(opts SolveOptions) ([]Action, int64, time.Duration, error) {
+	if opts.Backend == Backend_SAT {
+		return this.solveSAT(opts)
+	}
+	search := newSearch(this)
+	if opts.Memo {
+		search.deadStates = map[stateKey]bool{}
+	}
+	start := time.Now()
+	err := search.run(opts, nil)
+	return search.actions, search.n, time.Since(start), err
+}
+
+// search is the state of the depth first search of Solve: the board being
+// played, the unlocked tiles and one iterator per played action over the
+// pairs that can follow it.
+type search struct {
+	board         *Board
+	possibilities Possibilities
+	actions       []Action
+	iterators     []*iterator
+	remaining     int // tiles left on the board
+	heuristic     Heuristic
+	state         stateKey
+	everRemoved   stateKey // tiles removed in at least one of the states visited
+
+	// states proven to have no solution, nil to search without memory
+	deadStates map[stateKey]bool
+
+	n        int64 // number of iteration
+	nodes    int64 // number of iterators created with at least one pair
+	branches int64 // sum of their number of pairs
+}
+
+func newSearch(board *Board) *search {
+	this := &search{
+		board:         board,
+		possibilities: Possibilities{},
+		actions:       make([]Action, 0, 91),
+	}
+
+	// Fill the possibilities with all unlocked tiles
+	for i, tile := range board.Board {
+		if tile.Type != TileType_EMPTY {
+			this.remaining++
+			this.state.flip(i)
+			if !tile.Lock {
+				x, y := ToXYPos(i)
+				this.possibilities.Insert(tile.Type, Position{x, y})
+			}
+		}
+	}
+	this.possibilities.Sort()
+	return this
+}
+
+func (this *search) doAction(x1, y1, x2, y2 int) {
+	action := this.board.ApplyAction(x1, y1, x2, y2)
+
+	this.possibilities.Remove(Position{x1, y1})
+	this.remaining--
+	this.state.flip(FromXYPos(x1, y1))
+	this.everRemoved.set(FromXYPos(x1, y1))
+	if x1 != x2 || y1 != y2 {
+		this.possibilities.Remove(Position{x2, y2})
+		this.remaining--
+		this.state.flip(FromXYPos(x2, y2))
+		this.everRemoved.set(FromXYPos(x2, y2))
+	}
+
+	for _, pos := range action.Unlocked {
+		this.possibilities.Insert(this.board.Board[FromXYPos(pos.X, pos.Y)].Type, pos)
+	}
+
+	this.actions = append(this.actions, action)
+	this.possibilities.Sort()
+}
+
+func (this *search) undoLastAction() {
+	action := this.actions[len(this.actions)-1]
+
+	this.board.UndoAction(action)
+
+	this.possibilities.Insert(action.Type1, Position{action.X1, action.Y1})
+	this.remaining++
+	this.state.flip(FromXYPos(action.X1, action.Y1))
+	if action.X1 != action.X2 || action.Y1 != action.Y2 {
+		this.possibilities.Insert(action.Type2, Position{action.X2, action.Y2})
+		this.remaining++
+		this.state.flip(FromXYPos(action.X2, action.Y2))
+	}
+
+	for _, pos := range action.Unlocked {
+		this.possibilities.Remove(pos)
+	}
+
+	this.actions = this.actions[:len(this.actions)-1]
+	this.possibilities.Sort()
+}
+
+// stateKey identifies a state of the board by its remaining tiles, which is
+// enough to know the locks and the alchemy stage.
+type stateKey [2]uint64
+
+func (this *stateKey) flip(pos int) {
+	this[pos/64] ^= 1 << (pos % 64)
+}
+
+func (this *stateKey) set(pos int) {
+	this[pos/64] |= 1 << (pos % 64)
+}
+
+func (this stateKey) has(pos int) bool {
+	return this[pos/64]>>(pos%64)&1 == 1
+}
+
+// undoTo undoes the actions played after the first depth ones.
+func (this *search) undoTo(depth int) {
+	for len(this.actions) > depth {
+		this.undoLastAction()
+	}
+	this.iterators = nil
+}
+
+func (this *search) pushIterator() {
+	if this.deadStates[this.state] {
+		this.iterators = append(this.iterators, &iterator{})
+		return
+	}
+	it := newIterator(this.board, this.possibilities, this.heuristic)
+	this.iterators = append(this.iterators, it)
+	if len(it.foundPossibilities) > 0 {
+		this.nodes++
+		this.branches += int64(len(it.foundPossibilities))
+	}
+}
+
+// run searches from the current state until the board is empty. If
+// onSolution is set, it is called on each solution and the search goes on
+// while it returns true; the actions played by run are then undone.
+func (this *search) run(opts SolveOptions, onSolution func() bool) error {
+	var k int // used to show advancement
+	start := time.Now()
+	limit := this.n + opts.MaxChecks
+	depth := len(this.actions)
+	this.heuristic = opts.Heuristic
+	this.iterators = this.iterators[:0]
+	this.pushIterator()
+
+	for len(this.iterators) > 0 {
+		if this.remaining == 0 {
+			if onSolution == nil {
+				return nil
+			}
+			if !onSolution() {
+				this.undoTo(depth)
+				return nil
+			}
+			this.iterators = this.iterators[:len(this.iterators)-1]
+			this.undoLastAction()
+			continue
+		}
+
+		if opts.OnProgress != nil && k%10000 == 0 {
+			opts.OnProgress(Progress{Checks: this.n, Depth: len(this.actions), Remaining: this.remaining})
+		}
+		if k == 100000 {
+			if !opts.Quiet {
+				fmt.Println(this.n, time.Since(start), iteratorsToString(this.iterators))
+			}
+			k = 0
+		}
+		if opts.MaxChecks > 0 && this.n >= limit {
+			this.undoTo(depth)
+			return ErrSearchLimit
+		}
+		if opts.Context != nil && k%1024 == 0 && opts.Context.Err() != nil {
+			this.undoTo(depth)
+			return opts.Context.Err()
+		}
+		this.n++
+		k++
+
+		pos1, pos2, found := this.iterators[len(this.iterators)-1].Next()
+		if found {
+			this.doAction(pos1.X, pos1.Y, pos2.X, pos2.Y)
+			this.pushIterator()
+		} else {
+			// when searching all the solutions, a solution may have been
+			// found below
+			if this.deadStates != nil && onSolution == nil {
+				this.deadStates[this.state] = true
+			}
+			this.iterators = this.iterators[:len(this.iterators)-1]
+			if len(this.iterators) > 0 {
+				this.undoLastAction()
+			}
+		}
+	}
+	return nil
+}
+
+// ApplyAction removes the pair (x1,y1) (x2,y2) from the board and returns the
+// resulting action, with the positions it unlocked. The pair is not checked.
+func (this *Board) ApplyAction(x1, y1, x2, y2 int) Action {
+	action := Action{
+		X1: x1, Y1: y1, Type1: this.Board[FromXYPos(x1, y1)].Type,
+		X2: x2, Y2: y2, Type2: this.Board[FromXYPos(x2, y2)].Type,
+	}
+
+	this.TileTypesRemainingMap[action.Type1]--
+	this.TileTypesRemainingMap[action.Type2]--
+
+	if action.Type1 == TileType_WHITE && action.Type2 != TileType_WHITE {
+		if this.TileTypesRemainingMap[action.Type2]%2 == 1 {
+			this.WhiteUsedWithColored++
+		} else {
+			this.WhiteUsedWithColored--
+		}
+	}
+
+	this.Board[FromXYPos(x1, y1)].Type = TileType_EMPTY
+	if x1 != x2 || y1 != y2 {
+		this.Board[FromXYPos(x2, y2)].Type = TileType_EMPTY
+	}
+
+	if action.Type1.GetAlchemyStage() != AlchemyStage_0 || action.Type2.GetAlchemyStage() != AlchemyStage_0 {
+		this.AlchemyStage++
+	}
+
+	action.Unlocked = append(this.CheckLockFromTileRemoving(action.Type1, x1, y1), this.CheckLockFromTileRemoving(action.Type2, x2, y2)...)
+	return action
+}
+
+// UndoAction puts back the tiles removed by action, which must be the last
+// action applied to the board.
+func (this *Board) UndoAction(action Action) {
+	this.TileTypesRemainingMap[action.Type1]++
+	this.TileTypesRemainingMap[action.Type2]++
+
+	if action.Type1 == TileType_WHITE && action.Type2 != TileType_WHITE {
+		if this.TileTypesRemainingMap[action.Type2]%2 == 1 {
+			this.WhiteUsedWithColored++
+		} else {
+			this.WhiteUsedWithColored--
+		}
+	}
+
+	this.Board[FromXYPos(action.X1, action.Y1)].Type = action.Type1
+	if action.X1 != action.X2 || action.Y1 != action.Y2 {
+		this.Board[FromXYPos(action.X2, action.Y2)].Type = action.Type2
+	}
+
+	if action.Type1.GetAlchemyStage() != AlchemyStage_0 || action.Type2.GetAlchemyStage() != AlchemyStage_0 {
+		this.AlchemyStage--
+	}
+
+	for _, pos := range action.Unlocked {
+		this.Board[FromXYPos(pos.X, pos.Y)].Lock = true
+	}
+}
+
+func SolutionToString(actions []Action) string {
+	var s string
+	for _, action := range actions {
+		s += fmt.Sprintf("%6s %6s {x:%2d y:%2d} {x:%2d y:%2d}\n", action.Type1, action.Type2, action.X1, action.Y1, action.X2, action.Y2)
+	}
+	return s
+}
+
+type iterator struct {
+	n                  int
+	foundPossibilities []possibleSolution
+}
+
+type possibleSolution struct {
+	p1, p2 Position
+}
+
+func newIterator(board *Board, possibilities Possibilities, heuristic Heuristic) *iterator {
+	var foundPossibilities []possibleSolution
+
+	// KEY + (L1 | L2 | L3 | L4 | L5), the next metal only
+	if nextMetal := board.AlchemyStage.GetNextAlchemyType(); isMetal(nextMetal) {
+		for _, pos1 := range possibilities[TileType_KEY] {
+			for _, pos2 := range possibilities[nextMetal] {
+				foundPossibilities = append(foundPossibilities, possibleSolution{pos1, pos2})
+			}
+		}
+	}
+
+	// L6
+	if len(possibilities[TileType_L6]) == 1 {
+		foundPossibilities = append(foundPossibilities, possibleSolution{possibilities[TileType_L6][0], possibilities[TileType_L6][0]})
+	}
+
+	// LIGHT + DARK
+	for _, pos1 := range possibilities[TileType_LIGHT] {
+		for _, pos2 := range possibilities[TileType_DARK] {
+			foundPossibilities = append(foundPossibilities, possibleSolution{pos1, pos2})
+		}
+	}
+
+	// CYAN + CYAN
+	for i, pos1 := range possibilities[TileType_CYAN] {
+		for _, pos2 := range possibilities[TileType_CYAN][i+1:] {
+			foundPossibilities = append(foundPossibilities, possibleSolution{pos1, pos2})
+		}
+	}
+
+	// ORANGE + ORANGE
+	for i, pos1 := range possibilities[TileType_ORANGE] {
+		for _, pos2 := range possibilities[TileType_ORANGE][i+1:] {
+			foundPossibilities = append(foundPossibilities, possibleSolution{pos1, pos2})
+		}
+	}
+
+	// BLUE + BLUE
+	for i, pos1 := range possibilities[TileType_BLUE] {
+		for _, pos2 := range possibilities[TileType_BLUE][i+1:] {
+			foundPossibilities = append(foundPossibilities, possibleSolution{pos1, pos2})
+		}
+	}
+
+	// GREEN + GREEN
+	for i, pos1 := range possibilities[TileType_GREEN] {
+		for _, pos2 := range possibilities[TileType_GREEN][i+1:] {
+			foundPossibilities = append(foundPossibilities, possibleSolution{pos1, pos2})
+		}
+	}
+
+	// WHITE + ( CYAN | ORANGE | BLUE | GREEN | WHITE )
+	for i, pos1 := range possibilities[TileType_WHITE] {
+		if board.WhiteUsedWithColored < board.TileTypesRemainingMap[TileType_WHITE] || board.TileTypesRemainingMap[TileType_CYAN]%2 == 1 {
+			for _, pos2 := range possibilities[TileType_CYAN] {
+				foundPossibilities = append(foundPossibilities, possibleSolution{pos1, pos2})
+			}
+		}
+		if board.WhiteUsedWithColored < board.TileTypesRemainingMap[TileType_WHITE] || board.TileTypesRemainingMap[TileType_ORANGE]%2 == 1 {
+			for _, pos2 := range possibilities[TileType_ORANGE] {
+				foundPossibilities = append(foundPossibilities, possibleSolution{pos1, pos2})
+			}
+		}
+		if board.WhiteUsedWithColored < board.TileTypesRemainingMap[TileType_WHITE] || board.TileTypesRemainingMap[TileType_BLUE]%2 == 1 {
+			for _, pos2 := range possibilities[TileType_BLUE] {
+				foundPossibilities = append(foundPossibilities, possibleSolution{pos1, pos2})
+			}
+		}
+		if board.WhiteUsedWithColored < board.TileTypesRemainingMap[TileType_WHITE] || board.TileTypesRemainingMap[TileType_GREEN]%2 == 1 {
+			for _, pos2 := range possibilities[TileType_GREEN] {
+				foundPossibilities = append(foundPossibilities, possibleSolution{pos1, pos2})
+			}
+		}
+		if board.WhiteUsedWithColored < board.TileTypesRemainingMap[TileType_WHITE] {
+			for _, pos2 := range possibilities[TileType_WHITE][i+1:] {
+				foundPossibilities = append(foundPossibilities, possibleSolution{pos1, pos2})
+			}
+		}
+	}
+
+	currentStage := int(board.AlchemyStage)
+
+	// sort possibilities
+	if currentStage != AlchemyStage_FINAL && heuristic != Heuristic_NONE {
+		sort.Slice(foundPossibilities, func(i, j int) bool {
+			p11Dist := board.Board[FromXYPos(foundPossibilities[i].p1.X, foundPossibilities[i].p1.Y)].DistanceToAlchs[currentStage]
+			p21Dist := board.Board[FromXYPos(foundPossibilities[i].p2.X, foundPossibilities[i].p2.Y)].DistanceToAlchs[currentStage]
+			p12Dist := board.Board[FromXYPos(foundPossibilities[j].p1.X, foundPossibilities[j].p1.Y)].DistanceToAlchs[currentStage]
+			p22Dist := board.Board[FromXYPos(foundPossibilities[j].p2.X, foundPossibilities[j].p2.Y)].DistanceToAlchs[currentStage]
+
+			if p11Dist < p12Dist && p11Dist < p22Dist || (p21Dist < p12Dist && p21Dist < p22Dist) {
+				return true // i < j
+			} else if p11Dist > p12Dist && p11Dist > p22Dist || (p21Dist > p12Dist && p21Dist > p22Dist) {
+				return false // i > j
+			} else {
+				return p11Dist+p21Dist < p12Dist+p22Dist
+			}
+		})
+	}
+
+	return &iterator{
+		n:                  0,
+		foundPossibilities: foundPossibilities,
+	}
+}
+
+func (this *iterator) Next() (Position, Position, bool) {
+	if this == nil || this.n >= len(this.foundPossibilities) {
+		return Position{-1, -1}, Position{-1, -1}, false
+	}
+	p1, p2 := this.foundPossibilities[this.n].p1, this.foundPossibilities[this.n].p2
+	this.n++
+	return p1, p2, true
+}
+
+func iteratorsToString(its []*iterator) string {
+	var a []string
+	for _, it := range its {
+		a = append(a, fmt.Sprintf("%d/%d", it.n, len(it.foundPossibilities)))
+	}
+	return fmt.Sprintf("[ %s ]", strings.Join(a, ", "))
+}
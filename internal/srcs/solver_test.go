@@ -0,0 +1,189 @@
+package sigmarsolver
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// legalMoves returns the pairs Move accepts, by trying all of them.
+func legalMoves(board *Board) [][2]Position {
+	var free []Position
+	for i, tile := range board.Board {
+		if tile.Type != TileType_EMPTY && !tile.Lock {
+			x, y := ToXYPos(i)
+			free = append(free, Position{x, y})
+		}
+	}
+	var moves [][2]Position
+	for i, p1 := range free {
+		for _, p2 := range free[i:] {
+			if board.CheckMove(p1.X, p1.Y, p2.X, p2.Y) == nil {
+				moves = append(moves, [2]Position{p1, p2})
+			}
+		}
+	}
+	return moves
+}
+
+// randomWalks plays random legal moves on copies of the board until they
+// are stuck, calling check after each move.
+func randomWalks(t *testing.T, start Board, walks int, check func(board *Board, actions []Action)) {
+	t.Helper()
+	rng := rand.New(rand.NewSource(1))
+	for walk := 0; walk < walks; walk++ {
+		board := start.Clone()
+		var actions []Action
+		for moves := legalMoves(&board); len(moves) > 0; moves = legalMoves(&board) {
+			move := moves[rng.Intn(len(moves))]
+			action, err := board.Move(move[0].X, move[0].Y, move[1].X, move[1].Y)
+			if err != nil {
+				t.Fatal(err)
+			}
+			actions = append(actions, action)
+			check(&board, actions)
+		}
+	}
+}
+
+func TestUndoRestoresBoard(t *testing.T) {
+	for _, path := range knownBoards {
+		start := loadBoard(t, path)
+		randomWalks(t, start, 20, func(board *Board, actions []Action) {
+			before := board.Clone()
+			moves := legalMoves(board)
+			for _, move := range moves {
+				action, _ := board.Move(move[0].X, move[0].Y, move[1].X, move[1].Y)
+				board.UndoAction(action)
+				if !reflect.DeepEqual(*board, before) {
+					t.Fatalf("%s: after %s undoing %s does not restore the board", path, SolutionToString(actions), SolutionToString([]Action{action}))
+				}
+			}
+			if len(moves) == 0 {
+				// stuck, undo the whole walk
+				for i := len(actions) - 1; i >= 0; i-- {
+					board.UndoAction(actions[i])
+				}
+				if !reflect.DeepEqual(*board, start) {
+					t.Fatalf("%s: undoing %s does not restore the board", path, SolutionToString(actions))
+				}
+				for _, action := range actions {
+					board.ApplyAction(action.X1, action.Y1, action.X2, action.Y2)
+				}
+			}
+		})
+	}
+}
+
+// the pairs of newIterator are legal, each one once, and all the legal
+// pairs without salt are there: salts are only pruned when the colored
+// tiles they could pair with are better paired together
+func TestIteratorPairs(t *testing.T) {
+	for _, path := range knownBoards {
+		randomWalks(t, loadBoard(t, path), 10, func(board *Board, actions []Action) {
+			possibilities := newSearch(board).possibilities
+			alchemy := newIterator(board, possibilities, Heuristic_ALCHEMY).foundPossibilities
+			none := newIterator(board, possibilities, Heuristic_NONE).foundPossibilities
+
+			found := map[[2]Position]bool{}
+			for _, p := range alchemy {
+				key := [2]Position{p.p1, p.p2}
+				if found[key] || found[[2]Position{p.p2, p.p1}] && p.p1 != p.p2 {
+					t.Fatalf("%s: after %s %v is given twice", path, SolutionToString(actions), key)
+				}
+				found[key] = true
+				if err := board.CheckMove(p.p1.X, p.p1.Y, p.p2.X, p.p2.Y); err != nil {
+					t.Fatalf("%s: after %s %v", path, SolutionToString(actions), err)
+				}
+			}
+			if len(none) != len(alchemy) {
+				t.Fatalf("%s: after %s %d pairs without heuristic, %d with", path, SolutionToString(actions), len(none), len(alchemy))
+			}
+			for _, p := range none {
+				if !found[[2]Position{p.p1, p.p2}] {
+					t.Fatalf("%s: after %s %v is only given without heuristic", path, SolutionToString(actions), p)
+				}
+			}
+			for _, move := range legalMoves(board) {
+				type1, type2 := board.Board[FromXYPos(move[0].X, move[0].Y)].Type, board.Board[FromXYPos(move[1].X, move[1].Y)].Type
+				if type1 != TileType_WHITE && type2 != TileType_WHITE && !found[move] && !found[[2]Position{move[1], move[0]}] {
+					t.Fatalf("%s: after %s %s %v is missing", path, SolutionToString(actions), type1, move)
+				}
+			}
+		})
+	}
+}
+
+// a board with l1 twice: once the first l1 is removed, the second is no
+// longer the next metal, neither a move nor a pair of the search
+func TestMetalTwice(t *testing.T) {
+	board := NewBoard(tilesOf(map[Position]TileType{
+		{0, 0}: TileType_L1, {0, 2}: TileType_L1, {10, 0}: TileType_KEY, {10, 2}: TileType_KEY,
+	}))
+	if _, err := board.Move(0, 0, 10, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := board.Move(0, 2, 10, 2); !errors.Is(err, ErrIllegalMove) {
+		t.Errorf("second l1: %v, want %v", err, ErrIllegalMove)
+	}
+	if pairs := newIterator(&board, newSearch(&board).possibilities, Heuristic_ALCHEMY).foundPossibilities; len(pairs) != 0 {
+		t.Errorf("second l1: pairs %v", pairs)
+	}
+	if actions, _, _, err := board.SolveWithOptions(SolveOptions{Quiet: true}); err != nil || len(actions) != 0 {
+		t.Errorf("second l1: solved with %v %v", actions, err)
+	}
+}
+
+func checkSolution(t *testing.T, name string, tiles [][]TileType, actions []Action) {
+	t.Helper()
+	board := NewBoard(tiles)
+	for i, action := range actions {
+		if _, err := board.Move(action.X1, action.Y1, action.X2, action.Y2); err != nil {
+			t.Fatalf("%s: move %d: %v", name, i+1, err)
+		}
+	}
+	for i, tile := range board.Board {
+		if tile.Type != TileType_EMPTY {
+			x, y := ToXYPos(i)
+			t.Fatalf("%s: %s (%d,%d) is left", name, tile.Type, x, y)
+		}
+	}
+}
+
+func TestSolutionsVerify(t *testing.T) {
+	paths, _ := filepath.Glob("../../testdata/corpus/*.json")
+	paths = append(paths, knownBoards...)
+	for _, path := range paths {
+		tiles := loadTiles(t, path)
+		for _, opts := range []SolveOptions{{}, {Heuristic: Heuristic_NONE}, {Memo: true}} {
+			board := NewBoard(tiles)
+			opts.Quiet, opts.MaxChecks = true, 400000
+			actions, _, _, err := board.SolveWithOptions(opts)
+			if err == ErrSearchLimit {
+				// input3 takes millions of checks with the alchemy heuristic
+				t.Logf("%s: skipped with %+v: %v", path, opts, err)
+				continue
+			}
+			if err != nil || len(actions) == 0 {
+				t.Fatalf("%s: no solution with %+v: %v", path, opts, err)
+			}
+			checkSolution(t, path, tiles, actions)
+		}
+	}
+
+	for seed := int64(0); seed < 3; seed++ {
+		board, err := Generate(seed, GenerateOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		tiles := board.Tiles()
+		actions, _, _, err := board.SolveWithOptions(SolveOptions{Quiet: true})
+		if err != nil || len(actions) == 0 {
+			t.Fatalf("seed %d: no solution: %v", seed, err)
+		}
+		checkSolution(t, fmt.Sprintf("seed %d", seed), tiles, actions)
+	}
+}
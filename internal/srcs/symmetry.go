@@ -0,0 +1,148 @@
+package sigmarsolver
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Symmetry is one of the 12 symmetries of the board around its middle tile:
+// Symmetry%6 rotations of 60 degrees clockwise, after a mirror upside down
+// when Symmetry >= 6. Symmetric boards are solved by the symmetric actions.
+type Symmetry int
+
+const (
+	Symmetry_IDENTITY Symmetry = 0
+	Symmetry_MIRROR   Symmetry = 6
+
+	NbSymmetries = 12
+)
+
+func (this Symmetry) String() string {
+	switch {
+	case this == Symmetry_IDENTITY:
+		return "identity"
+	case this == Symmetry_MIRROR:
+		return "mirror"
+	case this > Symmetry_MIRROR:
+		return fmt.Sprintf("mirror, rotate %d", 60*(this%6))
+	default:
+		return fmt.Sprintf("rotate %d", 60*this)
+	}
+}
+
+// Inverse returns the symmetry undoing this one. Mirrors undo themselves.
+func (this Symmetry) Inverse() Symmetry {
+	if this >= Symmetry_MIRROR {
+		return this
+	}
+	return (6 - this) % 6
+}
+
+// Then returns the symmetry applying this one, then next.
+func (this Symmetry) Then(next Symmetry) Symmetry {
+	// an isometry fixing the middle tile is known by the image of two
+	// neighbouring tiles
+	a, b := Position{0, 0}, Position{0, 1}
+	for sym := Symmetry(0); sym < NbSymmetries; sym++ {
+		if sym.Position(a) == next.Position(this.Position(a)) && sym.Position(b) == next.Position(this.Position(b)) {
+			return sym
+		}
+	}
+	panic(fmt.Sprintf("no symmetry for %v then %v", this, next))
+}
+
+func (this Symmetry) Position(pos Position) Position {
+	if this >= Symmetry_MIRROR {
+		pos = mirrorPosition(pos)
+	}
+	return rotatePosition(pos, int(this%6))
+}
+
+// Tiles returns the tiles moved by the symmetry, in the format NewBoard
+// accepts.
+func (this Symmetry) Tiles(tiles [][]TileType) [][]TileType {
+	transformed := make([][]TileType, nbLines)
+	for x := range transformed {
+		transformed[x] = make([]TileType, lineSize[x])
+	}
+	for x, line := range tiles {
+		for y, tile := range line {
+			pos := this.Position(Position{x, y})
+			transformed[pos.X][pos.Y] = tile
+		}
+	}
+	return transformed
+}
+
+// Board returns a copy of the board moved by the symmetry. The metals
+// already removed and the whites used are kept.
+func (this Symmetry) Board(board Board) Board {
+	transformed := NewBoard(this.Tiles(board.Tiles()))
+	transformed.AlchemyStage = board.AlchemyStage
+	transformed.WhiteUsedWithColored = board.WhiteUsedWithColored
+	for x := 0; x < nbLines; x++ {
+		for y := 0; y < lineSize[x]; y++ {
+			transformed.CheckLockState(x, y)
+		}
+	}
+	return transformed
+}
+
+func (this Symmetry) Action(action Action) Action {
+	p1 := this.Position(Position{action.X1, action.Y1})
+	p2 := this.Position(Position{action.X2, action.Y2})
+	transformed := Action{X1: p1.X, Y1: p1.Y, X2: p2.X, Y2: p2.Y, Type1: action.Type1, Type2: action.Type2}
+	for _, pos := range action.Unlocked {
+		transformed.Unlocked = append(transformed.Unlocked, this.Position(pos))
+	}
+	return transformed
+}
+
+// Actions maps a solution of a board to the solution of the board moved by
+// the symmetry.
+func (this Symmetry) Actions(actions []Action) []Action {
+	transformed := make([]Action, len(actions))
+	for i, action := range actions {
+		transformed[i] = this.Action(action)
+	}
+	return transformed
+}
+
+// Canonical returns the orientation of the board shared by all its symmetric
+// boards, and the symmetry moving the board to it.
+func Canonical(board Board) (Board, Symmetry) {
+	tiles := board.Tiles()
+	bestSym := Symmetry_IDENTITY
+	bestKey := tilesString(tiles)
+	for sym := Symmetry(1); sym < NbSymmetries; sym++ {
+		if key := tilesString(sym.Tiles(tiles)); key < bestKey {
+			bestKey, bestSym = key, sym
+		}
+	}
+	return bestSym.Board(board), bestSym
+}
+
+// CanonicalKey returns a short hash of the tiles of the canonical board,
+// equal for symmetric boards.
+func CanonicalKey(board Board) string {
+	canonical, _ := Canonical(board)
+	return tilesKey(canonical.Tiles())
+}
+
+func tilesKey(tiles [][]TileType) string {
+	sum := sha256.Sum256([]byte(tilesString(tiles)))
+	return hex.EncodeToString(sum[:16])
+}
+
+func tilesString(tiles [][]TileType) string {
+	var sb strings.Builder
+	for _, line := range tiles {
+		for _, tile := range line {
+			sb.WriteString(string(tile))
+			sb.WriteByte(',')
+		}
+	}
+	return sb.String()
+}
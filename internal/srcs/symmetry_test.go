@@ -0,0 +1,77 @@
+package sigmarsolver
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// every symmetry moves the 91 tiles to 91 different tiles and neighbors to
+// neighbors
+func TestSymmetryPositions(t *testing.T) {
+	for sym := Symmetry(0); sym < NbSymmetries; sym++ {
+		images := map[Position]bool{}
+		for x := 0; x < nbLines; x++ {
+			for y := 0; y < lineSize[x]; y++ {
+				pos := Position{x, y}
+				image := sym.Position(pos)
+				if !IsPossitionValid(image.X, image.Y) || images[image] {
+					t.Fatalf("%v: %v moved to %v, invalid or taken", sym, pos, image)
+				}
+				images[image] = true
+
+				want := map[Position]bool{}
+				for _, neighbor := range getAllJoinedTiles(image.X, image.Y) {
+					want[neighbor] = true
+				}
+				got := map[Position]bool{}
+				for _, neighbor := range getAllJoinedTiles(x, y) {
+					got[sym.Position(neighbor)] = true
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Fatalf("%v: neighbors of %v moved to %v, want the neighbors of %v %v", sym, pos, got, image, want)
+				}
+			}
+		}
+		if sym.Then(sym.Inverse()) != Symmetry_IDENTITY {
+			t.Errorf("%v then its inverse %v is %v", sym, sym.Inverse(), sym.Then(sym.Inverse()))
+		}
+	}
+}
+
+// the solution of a board moved by a symmetry solves the moved board
+func TestSymmetrySolutions(t *testing.T) {
+	for _, path := range knownBoards {
+		tiles := loadTiles(t, path)
+		board := NewBoard(tiles)
+		actions, _, _, err := board.SolveWithOptions(SolveOptions{Quiet: true, MaxChecks: 400000})
+		if err == ErrSearchLimit {
+			// input3 takes millions of checks
+			continue
+		} else if err != nil || len(actions) == 0 {
+			t.Fatalf("%s: no solution: %v", path, err)
+		}
+		for sym := Symmetry(0); sym < NbSymmetries; sym++ {
+			checkSolution(t, fmt.Sprintf("%s %v", path, sym), sym.Tiles(tiles), sym.Actions(actions))
+		}
+	}
+}
+
+// the 12 images of a board share their canonical board
+func TestCanonical(t *testing.T) {
+	for _, path := range knownBoards {
+		board := loadBoard(t, path)
+		canonical, _ := Canonical(board)
+		want := canonical.Tiles()
+		for sym := Symmetry(0); sym < NbSymmetries; sym++ {
+			image := sym.Board(board)
+			got, moved := Canonical(image)
+			if !reflect.DeepEqual(got.Tiles(), want) || CanonicalKey(image) != CanonicalKey(board) {
+				t.Errorf("%s: canonical board of the %v image differs", path, sym)
+			}
+			if !reflect.DeepEqual(moved.Board(image).Tiles(), want) {
+				t.Errorf("%s: %v does not move the %v image to the canonical board", path, moved, sym)
+			}
+		}
+	}
+}
@@ -48,6 +48,12 @@ const (
 )
 
 func (this TileType) Valid() {
+	if !this.IsValid() {
+		panic(fmt.Sprintf("invalid %s", this))
+	}
+}
+
+func (this TileType) IsValid() bool {
 	switch this {
 	case TileType_EMPTY,
 		TileType_WHITE,
@@ -64,9 +70,9 @@ func (this TileType) Valid() {
 		TileType_L4,
 		TileType_L5,
 		TileType_L6:
-		return
+		return true
 	default:
-		panic(fmt.Sprintf("invalid %s", this))
+		return false
 	}
 }
 
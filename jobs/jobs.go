@@ -0,0 +1,341 @@
+// Package jobs runs solves in the background: boards are submitted to a
+// queue, worked on by a fixed number of workers and their results kept for
+// a while to be polled.
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	. "sigmars-garden-solver/internal/srcs"
+)
+
+type Status string
+
+const (
+	Status_QUEUED   Status = "queued"
+	Status_RUNNING  Status = "running"
+	Status_DONE     Status = "done"
+	Status_FAILED   Status = "failed"
+	Status_CANCELED Status = "canceled"
+)
+
+// Finished tells if the job will not change anymore.
+func (this Status) Finished() bool {
+	return this == Status_DONE || this == Status_FAILED || this == Status_CANCELED
+}
+
+var (
+	ErrNotFound    = errors.New("job not found")
+	ErrFull        = errors.New("too many jobs")
+	ErrNotFinished = errors.New("job not finished")
+	ErrClosed      = errors.New("manager closed")
+)
+
+type Result struct {
+	Solved   bool
+	Actions  []Action
+	Checks   int64
+	Duration time.Duration
+}
+
+// Job is a snapshot of a job, returned by the manager.
+type Job struct {
+	ID       string
+	Status   Status
+	Progress Progress
+	Error    string // why the job failed
+	Result   *Result
+
+	Submitted, Started, Finished time.Time
+}
+
+type Options struct {
+	Workers   int           // jobs solved at the same time, 2 if 0
+	MaxJobs   int           // jobs kept, queued and finished ones included, 100 if 0
+	TTL       time.Duration // time a finished job is kept, 10m if 0
+	MaxChecks int64         // checks allowed to a solve, 0 for no limit
+	Timeout   time.Duration // time allowed to a solve, 0 for no limit
+
+	// Now returns the current time, time.Now if nil. Only the expiration of
+	// the jobs depends on it.
+	Now func() time.Time
+}
+
+type job struct {
+	Job
+	board  Board
+	cancel context.CancelFunc
+}
+
+type Manager struct {
+	opts Options
+
+	mutex  sync.Mutex
+	ready  *sync.Cond // signaled when a job is queued or the manager closed
+	queue  []*job     // queued jobs, canceled ones are removed
+	jobs   map[string]*job
+	closed bool
+	wg     sync.WaitGroup
+}
+
+func NewManager(opts Options) *Manager {
+	if opts.Workers <= 0 {
+		opts.Workers = 2
+	}
+	if opts.MaxJobs <= 0 {
+		opts.MaxJobs = 100
+	}
+	if opts.TTL <= 0 {
+		opts.TTL = 10 * time.Minute
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+
+	this := &Manager{
+		opts: opts,
+		jobs: map[string]*job{},
+	}
+	this.ready = sync.NewCond(&this.mutex)
+	for i := 0; i < opts.Workers; i++ {
+		this.wg.Add(1)
+		go this.work()
+	}
+	return this
+}
+
+// Submit queues a solve of the board and returns the ID of its job. When
+// MaxJobs are kept, the oldest finished job is dropped to make room, or
+// ErrFull is returned if none is finished.
+func (this *Manager) Submit(board Board) (string, error) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if this.closed {
+		return "", ErrClosed
+	}
+	this.expire()
+	if len(this.jobs) >= this.opts.MaxJobs && !this.dropOldestFinished() {
+		return "", ErrFull
+	}
+
+	j := &job{board: board.Clone()}
+	j.ID = newID()
+	j.Status = Status_QUEUED
+	j.Submitted = this.opts.Now()
+	this.queue = append(this.queue, j)
+	this.jobs[j.ID] = j
+	this.ready.Signal()
+	return j.ID, nil
+}
+
+// Get returns a snapshot of the job, with its live progress while it runs.
+func (this *Manager) Get(id string) (Job, error) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	this.expire()
+	j, ok := this.jobs[id]
+	if !ok {
+		return Job{}, ErrNotFound
+	}
+	return j.Job, nil
+}
+
+// List returns a snapshot of every job kept, the oldest first.
+func (this *Manager) List() []Job {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	this.expire()
+	list := make([]Job, 0, len(this.jobs))
+	for _, j := range this.jobs {
+		list = append(list, j.Job)
+	}
+	sort.Slice(list, func(i, k int) bool { return list[i].Submitted.Before(list[k].Submitted) })
+	return list
+}
+
+// Result returns the result of a job that is done, or why there is none.
+func (this *Manager) Result(id string) (Result, error) {
+	j, err := this.Get(id)
+	if err != nil {
+		return Result{}, err
+	}
+	switch j.Status {
+	case Status_DONE:
+		return *j.Result, nil
+	case Status_FAILED:
+		return Result{}, errors.New(j.Error)
+	case Status_CANCELED:
+		return Result{}, context.Canceled
+	default:
+		return Result{}, ErrNotFinished
+	}
+}
+
+// Cancel stops a queued or running job. Canceling a finished job does
+// nothing.
+func (this *Manager) Cancel(id string) error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	j, ok := this.jobs[id]
+	if !ok {
+		return ErrNotFound
+	}
+	switch j.Status {
+	case Status_QUEUED:
+		for i, queued := range this.queue {
+			if queued == j {
+				this.queue = append(this.queue[:i], this.queue[i+1:]...)
+				break
+			}
+		}
+		this.finish(j, Status_CANCELED, nil, "")
+	case Status_RUNNING:
+		j.cancel()
+	}
+	return nil
+}
+
+// Close cancels every job and waits for the workers to stop.
+func (this *Manager) Close() {
+	this.mutex.Lock()
+	if this.closed {
+		this.mutex.Unlock()
+		return
+	}
+	this.closed = true
+	for _, j := range this.jobs {
+		if j.Status == Status_QUEUED {
+			this.finish(j, Status_CANCELED, nil, "")
+		} else if j.Status == Status_RUNNING {
+			j.cancel()
+		}
+	}
+	this.queue = nil
+	this.ready.Broadcast()
+	this.mutex.Unlock()
+
+	this.wg.Wait()
+}
+
+func (this *Manager) work() {
+	defer this.wg.Done()
+
+	for {
+		this.mutex.Lock()
+		for len(this.queue) == 0 && !this.closed {
+			this.ready.Wait()
+		}
+		if this.closed {
+			this.mutex.Unlock()
+			return
+		}
+		j := this.queue[0]
+		this.queue = this.queue[1:]
+
+		ctx, cancel := context.Background(), context.CancelFunc(func() {})
+		if this.opts.Timeout > 0 {
+			ctx, cancel = context.WithTimeout(ctx, this.opts.Timeout)
+		}
+		ctx, cancelJob := context.WithCancel(ctx)
+		j.Status = Status_RUNNING
+		j.Started = this.opts.Now()
+		j.cancel = cancelJob
+		this.mutex.Unlock()
+
+		actions, n, dur, err := this.solve(&j.board, SolveOptions{
+			MaxChecks: this.opts.MaxChecks,
+			Quiet:     true,
+			Context:   ctx,
+			OnProgress: func(progress Progress) {
+				this.mutex.Lock()
+				j.Progress = progress
+				this.mutex.Unlock()
+			},
+		})
+		cancelJob()
+		cancel()
+
+		this.mutex.Lock()
+		j.Progress.Checks = n
+		switch {
+		case errors.Is(err, context.Canceled):
+			this.finish(j, Status_CANCELED, nil, "")
+		case err != nil:
+			this.finish(j, Status_FAILED, nil, err.Error())
+		default:
+			if len(actions) > 0 {
+				j.Progress.Depth, j.Progress.Remaining = len(actions), 0
+			}
+			this.finish(j, Status_DONE, &Result{Solved: len(actions) > 0, Actions: actions, Checks: n, Duration: dur}, "")
+		}
+		this.mutex.Unlock()
+	}
+}
+
+// solve runs the search of a job, a panic failing the job instead of
+// crashing the program.
+func (this *Manager) solve(board *Board, opts SolveOptions) (actions []Action, n int64, dur time.Duration, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return solveWithOptions(board, opts)
+}
+
+// solveWithOptions is replaced by the tests.
+var solveWithOptions = (*Board).SolveWithOptions
+
+// finish must be called with the mutex held.
+func (this *Manager) finish(j *job, status Status, result *Result, message string) {
+	j.Status = status
+	j.Result = result
+	j.Error = message
+	j.Finished = this.opts.Now()
+	j.board = Board{}
+}
+
+// expire drops the jobs finished for longer than the TTL. It must be called
+// with the mutex held.
+func (this *Manager) expire() {
+	now := this.opts.Now()
+	for id, j := range this.jobs {
+		if j.Status.Finished() && now.Sub(j.Finished) > this.opts.TTL {
+			delete(this.jobs, id)
+		}
+	}
+}
+
+// dropOldestFinished must be called with the mutex held.
+func (this *Manager) dropOldestFinished() bool {
+	var oldest *job
+	for _, j := range this.jobs {
+		if j.Status.Finished() && (oldest == nil || j.Finished.Before(oldest.Finished)) {
+			oldest = j
+		}
+	}
+	if oldest == nil {
+		return false
+	}
+	delete(this.jobs, oldest.ID)
+	return true
+}
+
+func newID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
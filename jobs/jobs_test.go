@@ -0,0 +1,223 @@
+package jobs
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"sync"
+	"testing"
+	"time"
+
+	. "sigmars-garden-solver/internal/srcs"
+)
+
+func loadBoard(t *testing.T, path string) Board {
+	t.Helper()
+	byteValue, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tiles [][]TileType
+	if err := json.Unmarshal(byteValue, &tiles); err != nil {
+		t.Fatal(err)
+	}
+	board, err := ParseBoard(tiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return board
+}
+
+// quick is solved in a few checks, slow has no solution and runs until
+// canceled
+func boards(t *testing.T) (quick, slow Board) {
+	return loadBoard(t, "../inputs/input1.json"), loadBoard(t, "../inputs/input_invalid.json")
+}
+
+// waitStatus polls the job until it has the status.
+func waitStatus(t *testing.T, manager *Manager, id string, status Status) Job {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		j, err := manager.Get(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if j.Status == status {
+			return j
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job %s is %s, want %s", id, j.Status, status)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSubmitDone(t *testing.T) {
+	quick, _ := boards(t)
+	manager := NewManager(Options{})
+	defer manager.Close()
+
+	id, err := manager.Submit(quick)
+	if err != nil {
+		t.Fatal(err)
+	}
+	j := waitStatus(t, manager, id, Status_DONE)
+	if j.Result == nil || !j.Result.Solved || j.Progress.Remaining != 0 {
+		t.Fatalf("done job: %+v", j)
+	}
+	result, err := manager.Result(id)
+	if err != nil || len(result.Actions) != len(j.Result.Actions) {
+		t.Errorf("result of %d moves: %v", len(result.Actions), err)
+	}
+	if list := manager.List(); len(list) != 1 || list[0].ID != id {
+		t.Errorf("list %+v", list)
+	}
+}
+
+// a panic of the solver fails its job, the worker goes on with the next one
+func TestPanic(t *testing.T) {
+	panicked := false
+	solveWithOptions = func(board *Board, opts SolveOptions) ([]Action, int64, time.Duration, error) {
+		if !panicked {
+			panicked = true
+			panic("out of tiles")
+		}
+		return board.SolveWithOptions(opts)
+	}
+	defer func() { solveWithOptions = (*Board).SolveWithOptions }()
+
+	quick, _ := boards(t)
+	manager := NewManager(Options{Workers: 1})
+	defer manager.Close()
+
+	failed, err := manager.Submit(quick)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if j := waitStatus(t, manager, failed, Status_FAILED); j.Error != "panic: out of tiles" {
+		t.Errorf("error %q", j.Error)
+	}
+	done, err := manager.Submit(quick)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitStatus(t, manager, done, Status_DONE)
+}
+
+func TestCancel(t *testing.T) {
+	_, slow := boards(t)
+	manager := NewManager(Options{Workers: 1})
+	defer manager.Close()
+
+	running, err := manager.Submit(slow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	queued, err := manager.Submit(slow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitStatus(t, manager, running, Status_RUNNING)
+	if j, _ := manager.Get(queued); j.Status != Status_QUEUED {
+		t.Fatalf("second job is %s, want %s", j.Status, Status_QUEUED)
+	}
+
+	if err := manager.Cancel(queued); err != nil {
+		t.Fatal(err)
+	}
+	waitStatus(t, manager, queued, Status_CANCELED)
+	if err := manager.Cancel(running); err != nil {
+		t.Fatal(err)
+	}
+	waitStatus(t, manager, running, Status_CANCELED)
+	if _, err := manager.Result(running); err == nil {
+		t.Error("result of a canceled job")
+	}
+	if err := manager.Cancel("unknown"); err != ErrNotFound {
+		t.Errorf("cancel of an unknown job: %v", err)
+	}
+}
+
+func TestFull(t *testing.T) {
+	_, slow := boards(t)
+	manager := NewManager(Options{Workers: 1, MaxJobs: 2})
+	defer manager.Close()
+
+	running, err := manager.Submit(slow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitStatus(t, manager, running, Status_RUNNING)
+	queued, err := manager.Submit(slow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := manager.Submit(slow); err != ErrFull {
+		t.Fatalf("third job: %v, want %v", err, ErrFull)
+	}
+
+	// the canceled jobs make room, the worker being still busy
+	for i := 0; i < 5; i++ {
+		if err := manager.Cancel(queued); err != nil {
+			t.Fatal(err)
+		}
+		if queued, err = manager.Submit(slow); err != nil {
+			t.Fatalf("job %d after a cancel: %v", i, err)
+		}
+	}
+}
+
+type clock struct {
+	mutex sync.Mutex
+	now   time.Time
+}
+
+func (this *clock) Now() time.Time {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	return this.now
+}
+
+func (this *clock) Add(d time.Duration) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	this.now = this.now.Add(d)
+}
+
+func TestExpire(t *testing.T) {
+	quick, _ := boards(t)
+	clock := &clock{now: time.Unix(0, 0)}
+	manager := NewManager(Options{TTL: time.Minute, Now: clock.Now})
+	defer manager.Close()
+
+	id, err := manager.Submit(quick)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitStatus(t, manager, id, Status_DONE)
+	clock.Add(time.Minute)
+	if _, err := manager.Get(id); err != nil {
+		t.Fatalf("job dropped at its TTL: %v", err)
+	}
+	clock.Add(time.Second)
+	if _, err := manager.Get(id); err != ErrNotFound {
+		t.Errorf("job kept after its TTL: %v", err)
+	}
+}
+
+func TestClose(t *testing.T) {
+	quick, slow := boards(t)
+	manager := NewManager(Options{Workers: 1})
+	running, _ := manager.Submit(slow)
+	queued, _ := manager.Submit(slow)
+	manager.Close()
+
+	for _, id := range []string{running, queued} {
+		if j, err := manager.Get(id); err != nil || j.Status != Status_CANCELED {
+			t.Errorf("job %s after close: %s %v", id, j.Status, err)
+		}
+	}
+	if _, err := manager.Submit(quick); err != ErrClosed {
+		t.Errorf("submit after close: %v", err)
+	}
+}
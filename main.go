@@ -2,36 +2,138 @@ package main
 
 import (
 	"encoding/json"
+	"flag"
 	"fmt"
-	"io/ioutil"
 	"os"
-	. "sigmars-garden-solver/srcs"
+	. "sigmars-garden-solver/internal/srcs"
+	"strings"
+	"text/tabwriter"
 )
 
+type command struct {
+	name     string
+	synopsis string // the arguments of the command
+	summary  string
+	main     func(args []string)
+}
+
+// commands are filled by init, as helpMain lists them.
+var commands []command
+
+func init() {
+	commands = []command{
+		{"solve", "[-cache .cache] [-format text|json] [-timeout 10s] [-heuristic alchemy] [-q|-v] [board.json]", "solve a board", solveMain},
+		{"validate", "[-format text|json] [-q] [board.json]", "check a board without searching it", validateMain},
+		{"hint", "[-format text|json] [-timeout 10s] [-q] [board.json]", "give a move that keeps the board solvable", hintMain},
+		{"verify", "-solution solution.json [-format text|json] [-q] [board.json]", "check that a solution solves a board", verifyMain},
+		{"bench", "[-n 3] [-solver srcs] [-compare v0] [-timeout 1m] [-format text|json] inputs/ board.json...", "time the solvers on boards", benchMain},
+		{"batch", "[-workers 4] [-timeout 30s] [-o report.json] [-format text|json] inputs/ manifest.txt...", "solve many boards concurrently and report", batchMain},
+		{"render", "[-o board.svg] [-size 24] [-solution] [board.json]", "draw a board as SVG or PNG", renderMain},
+		{"generate", "[-seed 42] [-n 1] [-o dir]", "generate random solvable boards", generateMain},
+		{"play", "[-color] [-max-checks 1000000] board.json", "play a board in the terminal", playMain},
+		{"replay", "[-delay 500ms] [-color] board.json", "replay the solution of a board in the terminal", replayMain},
+		{"moves", "[-max-checks 1000000] [-color] board.json", "tell which moves keep the board solvable", movesMain},
+		{"explain", "[-max-checks 10000000] [-o dead-end.svg] [-color] board.json", "explain why a board has no solution", explainMain},
+		{"rate", "[-max-checks 1000000] inputs/ board.json...", "rate the difficulty of boards", rateMain},
+		{"diff", "[-seed 1] [-n 20] [-swaps 2] [-solvers srcs,srcs-sat] [-timeout 10s] [-o dir] [-format text|json]", "compare the solvers on generated boards", diffMain},
+		{"minimize", "[-until disagree|checks|panic] [-solver srcs] [-solvers srcs,srcs-sat] [-checks 100000] [-timeout 10s] [-o board.json] [board.json]", "shrink a board keeping a problem of the solvers", minimizeMain},
+		{"dedup", "inputs/ board.json...", "list the boards equal by rotation or mirror", dedupMain},
+		{"parse", "[-calibration calibration.json] [-overlay debug.png] screenshot.png", "read a board from a screenshot (experimental)", parseMain},
+		{"clicks", "-calibration calibration.json [-format xdotool|ahk|json] board.json", "turn a solution in a click script", clicksMain},
+		{"serve", "[-addr localhost:8080] [-timeout 30s] [-concurrency 4] [-ui]", "serve the solver over HTTP", serveMain},
+		{"help", "[command]", "print the help of a command", helpMain},
+	}
+}
+
+// Boards are read from the file given, or from the standard input. The
+// commands exit with an ExitCode.
 func main() {
-	if len(os.Args) == 2 {
-		f, err := os.Open(os.Args[1])
-		if err != nil {
-			panic(err)
+	if len(os.Args) < 2 {
+		helpMain(nil)
+		os.Exit(int(ExitCode_INVALID))
+	}
+	for _, c := range commands {
+		if c.name == os.Args[1] {
+			c.main(os.Args[2:])
+			return
+		}
+	}
+	if os.Args[1] == "-h" || os.Args[1] == "-help" || os.Args[1] == "--help" {
+		helpMain(nil)
+		return
+	}
+	if len(os.Args) == 2 && strings.HasSuffix(os.Args[1], ".json") {
+		// the original usage, solving the board given
+		solveMain(os.Args[1:])
+		return
+	}
+	fail(ExitCode_INVALID, "unknown command %q, see %s help", os.Args[1], os.Args[0])
+}
+
+func helpMain(args []string) {
+	if len(args) == 1 {
+		for _, c := range commands {
+			if c.name == args[0] {
+				c.main([]string{"-h"})
+				return
+			}
 		}
+		fail(ExitCode_INVALID, "unknown command %q", args[0])
+	}
 
-		byteValue, _ := ioutil.ReadAll(f)
-		var tiles [][]TileType
+	fmt.Printf("usage: %s <command> [flags] [board.json]\n\n", os.Args[0])
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %s\t%s\n", c.name, c.summary)
+	}
+	w.Flush()
+	fmt.Printf("\nBoards are read from stdin when no file is given. %s <command> -h prints the flags of a command.\n", os.Args[0])
+	fmt.Println("Exit codes: 0 solved or done, 1 unsolvable, 2 invalid input, 3 timeout or search limit.")
+}
 
-		if err := json.Unmarshal(byteValue, &tiles); err != nil {
-			panic(err)
+// newFlagSet returns the flags of a command, printing its synopsis and its
+// flags on -h or on invalid flags.
+func newFlagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	flags.Usage = func() {
+		for _, c := range commands {
+			if c.name == name {
+				fmt.Fprintf(flags.Output(), "usage: %s %s %s\n%s\n\n", os.Args[0], c.name, c.synopsis, c.summary)
+			}
 		}
+		flags.PrintDefaults()
+	}
+	return flags
+}
 
-		board := NewBoard(tiles)
+// loadTiles is readTiles exiting with ExitCode_INVALID when the file cannot
+// be read.
+func loadTiles(path string) [][]TileType {
+	tiles, err := readTiles(path)
+	if err != nil {
+		fail(ExitCode_INVALID, "%v", err)
+	}
+	return tiles
+}
 
-		actions, n, dur := board.Solve()
+// loadBoard is readBoard exiting with ExitCode_INVALID when the board is
+// invalid.
+func loadBoard(path string) Board {
+	board, err := readBoard(path)
+	if err != nil {
+		fail(ExitCode_INVALID, "%v", err)
+	}
+	return board
+}
 
-		fmt.Println("total checks:", n)
-		fmt.Println("total duration:", dur)
-		fmt.Println(SolutionToString(actions))
-	} else {
-		fmt.Printf("%s inputs/input1.json\n", os.Args[0])
+// formatTiles writes the tiles like the files of inputs/, one line per row.
+func formatTiles(tiles [][]TileType) string {
+	lines := make([]string, len(tiles))
+	for i, line := range tiles {
+		byteValue, _ := json.Marshal(line)
+		lines[i] = "    " + strings.ReplaceAll(string(byteValue), ",", ", ")
 	}
+	return fmt.Sprintf("[\n%s\n]\n", strings.Join(lines, ",\n"))
 }
 
 // var tiles = [][]TileType{
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	. "sigmars-garden-solver/internal/srcs"
+)
+
+// the test binary runs the command of its arguments when SIGMAR_MAIN is
+// set, so that the tests can check its exit code
+func TestMain(m *testing.M) {
+	if os.Getenv("SIGMAR_MAIN") != "" {
+		main()
+		os.Exit(int(ExitCode_SOLVED))
+	}
+	os.Exit(m.Run())
+}
+
+// runMain runs the command in a subprocess and returns its exit code.
+func runMain(t *testing.T, args ...string) ExitCode {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Env = append(os.Environ(), "SIGMAR_MAIN=1")
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return ExitCode(exitErr.ExitCode())
+	} else if err != nil {
+		t.Fatal(err)
+	}
+	return ExitCode_SOLVED
+}
+
+// writeTiles writes a board of the tiles given by position, the other ones
+// empty.
+func writeTiles(t *testing.T, tiles map[[2]int]string) string {
+	t.Helper()
+	lines := make([][]TileType, 11)
+	for x := range lines {
+		lines[x] = make([]TileType, 11-abs(x-5))
+	}
+	for pos, tileType := range tiles {
+		lines[pos[0]][pos[1]] = TileType(tileType)
+	}
+	return writeFile(t, "board.json", formatTiles(lines))
+}
+
+// writeFile writes the content in a temporary file and returns its path.
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func TestExplainExitCode(t *testing.T) {
+	lights := writeTiles(t, map[[2]int]string{{0, 0}: "light", {10, 5}: "light"})
+	tests := []struct {
+		args []string
+		want ExitCode
+	}{
+		{[]string{"explain", "inputs/input1.json"}, ExitCode_SOLVED},
+		{[]string{"explain", lights}, ExitCode_UNSOLVABLE},
+		{[]string{"explain", "-max-checks", "1", "testdata/corpus/generated-142.json"}, ExitCode_TIMEOUT},
+		{[]string{"explain", "-o", "/nonexistent/dead-end.svg", "inputs/input1.json"}, ExitCode_INVALID},
+		{[]string{"explain", "nonexistent.json"}, ExitCode_INVALID},
+	}
+	for _, test := range tests {
+		if code := runMain(t, test.args...); code != test.want {
+			t.Errorf("%v: exit code %d, want %d", test.args, code, test.want)
+		}
+	}
+}
+
+// input_invalid takes a long search to tell it has no solution: solve and
+// batch prove it with the SAT backend, validate does not search it and hint
+// is given two lights instead. validate and verify have no timeout.
+func TestExitCodes(t *testing.T) {
+	tiles, err := readTiles("inputs/input1.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	board := NewBoard(tiles)
+	actions, _, _, err := board.SolveWithOptions(SolveOptions{Quiet: true})
+	if err != nil || len(actions) == 0 {
+		t.Fatalf("input1 not solved: %v", err)
+	}
+	byteValue, _ := json.Marshal(actions)
+	solution := writeFile(t, "solution.json", string(byteValue))
+	lights := writeTiles(t, map[[2]int]string{{0, 0}: "light", {10, 5}: "light"})
+	malformed := writeFile(t, "malformed.json", `[["white", `)
+
+	tests := []struct {
+		args []string
+		want ExitCode
+	}{
+		{[]string{"solve", "inputs/input1.json"}, ExitCode_SOLVED},
+		{[]string{"solve", "-backend", "sat", "inputs/input_invalid.json"}, ExitCode_UNSOLVABLE},
+		{[]string{"solve", malformed}, ExitCode_INVALID},
+		{[]string{"solve", "-timeout", "1ns", "inputs/input3.json"}, ExitCode_TIMEOUT},
+
+		{[]string{"validate", "inputs/input1.json"}, ExitCode_SOLVED},
+		{[]string{"validate", "inputs/input_invalid.json"}, ExitCode_SOLVED},
+		{[]string{"validate", lights}, ExitCode_UNSOLVABLE},
+		{[]string{"validate", malformed}, ExitCode_INVALID},
+
+		{[]string{"verify", "-solution", solution, "inputs/input1.json"}, ExitCode_SOLVED},
+		{[]string{"verify", "-solution", solution, "inputs/input_invalid.json"}, ExitCode_UNSOLVABLE},
+		{[]string{"verify", "-solution", solution, malformed}, ExitCode_INVALID},
+		{[]string{"verify", "-solution", malformed, "inputs/input1.json"}, ExitCode_INVALID},
+
+		{[]string{"hint", "inputs/input1.json"}, ExitCode_SOLVED},
+		{[]string{"hint", lights}, ExitCode_UNSOLVABLE},
+		{[]string{"hint", malformed}, ExitCode_INVALID},
+		{[]string{"hint", "-timeout", "1ns", "inputs/input_invalid.json"}, ExitCode_TIMEOUT},
+
+		{[]string{"batch", "inputs/input1.json", "inputs/input2.json"}, ExitCode_SOLVED},
+		{[]string{"batch", "-backend", "sat", "inputs/input1.json", "inputs/input_invalid.json"}, ExitCode_UNSOLVABLE},
+		{[]string{"batch", "inputs/input1.json", malformed}, ExitCode_INVALID},
+		{[]string{"batch", "-timeout", "1ns", "inputs/input1.json", "inputs/input3.json"}, ExitCode_TIMEOUT},
+	}
+	for _, test := range tests {
+		if code := runMain(t, test.args...); code != test.want {
+			t.Errorf("%v: exit code %d, want %d", test.args, code, test.want)
+		}
+	}
+}
+
+// each run stopped by the timeout would leave a v0 search running
+func TestUnbounded(t *testing.T) {
+	tests := [][]string{
+		{"diff", "-n", "1", "-solvers", "srcs,v0", "-timeout", "1s"},
+		{"bench", "-solver", "v0", "-timeout", "1s", "inputs/input1.json"},
+		{"bench", "-compare", "v0", "-timeout", "1s", "inputs/input1.json"},
+		{"minimize", "-until", "disagree", "-solvers", "srcs,v0", "-timeout", "1s", "inputs/input1.json"},
+		{"minimize", "-until", "checks", "-solver", "v0", "-timeout", "1s", "inputs/input1.json"},
+		{"minimize", "-until", "panic", "-solver", "v0", "-timeout", "1s", "inputs/input1.json"},
+	}
+	for _, args := range tests {
+		if code := runMain(t, args...); code != ExitCode_INVALID {
+			t.Errorf("%v: exit code %d, want %d", args, code, ExitCode_INVALID)
+		}
+	}
+}
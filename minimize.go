@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	. "sigmars-garden-solver/internal/srcs"
+	"sigmars-garden-solver/solvers"
+	"strings"
+)
+
+// minimizeMain shrinks a board while it still shows a problem: the solvers
+// disagree on it, the solver takes too many checks or panics. The smallest
+// board found is printed, or written in -o.
+func minimizeMain(args []string) {
+	flags := newFlagSet("minimize")
+	until := flags.String("until", "disagree", "problem kept by the board: disagree, checks or panic")
+	solverName := flags.String("solver", solvers.All[0].Name, "solver of -until checks and panic, see bench -list")
+	names := flags.String("solvers", "srcs,srcs-sat", "solvers of -until disagree")
+	checks := flags.Int64("checks", 100000, "checks the solver must exceed with -until checks")
+	output := flags.String("o", "", "write the board in this file instead of the standard output")
+	quiet := flags.Bool("q", false, "print only the board")
+	common := (&boardFlags{}).addSearchFlags(flags)
+	flags.Parse(args)
+
+	tiles := loadTiles(inputArg(flags))
+	if _, err := ParseBoard(tiles); err != nil {
+		fail(ExitCode_INVALID, "%v", err)
+	}
+	limits := solvers.Limits{Timeout: *common.timeout, MaxChecks: *common.maxChecks}
+	solver, err := solvers.Get(*solverName)
+	if err != nil {
+		fail(ExitCode_INVALID, "-solver: %v", err)
+	}
+
+	bounded := func(flag string, solver solvers.Solver) {
+		if err := solver.Bounded(limits); err != nil {
+			fail(ExitCode_INVALID, "%s: %v", flag, err)
+		}
+	}
+
+	var holds func(tiles [][]TileType) bool
+	switch *until {
+	case "disagree":
+		var list []solvers.Solver
+		for _, name := range strings.Split(*names, ",") {
+			solver, err := solvers.Get(strings.TrimSpace(name))
+			if err != nil {
+				fail(ExitCode_INVALID, "-solvers: %v", err)
+			}
+			bounded("-solvers", solver)
+			list = append(list, solver)
+		}
+		runs := solvers.RunAll(list, tiles, limits)
+		if !solvers.Disagree(runs) {
+			fail(ExitCode_INVALID, "the solvers agree on the board: %s", formatRuns(runs))
+		}
+		holds = solvers.KeepsVerdicts(list, runs, limits)
+	case "checks":
+		bounded("-solver", solver)
+		holds = solvers.ExceedsChecks(solver, *checks, limits)
+		if !holds(tiles) {
+			fail(ExitCode_INVALID, "%s solves the board within %d checks", solver.Name, *checks)
+		}
+	case "panic":
+		bounded("-solver", solver)
+		message := solvers.Panic(solver, tiles, limits)
+		if message == "" {
+			fail(ExitCode_INVALID, "%s does not panic on the board", solver.Name)
+		}
+		holds = solvers.PanicsWith(solver, message, limits)
+	default:
+		fail(ExitCode_INVALID, "-until: %q is not disagree, checks nor panic", *until)
+	}
+
+	tries := 0
+	minimized := Minimize(tiles, func(tiles [][]TileType) bool {
+		tries++
+		return holds(tiles)
+	})
+	if !*quiet {
+		fmt.Fprintf(os.Stderr, "minimized from %d to %d tiles in %d tries\n", countTiles(tiles), countTiles(minimized), tries)
+	}
+	if *output == "" {
+		fmt.Print(formatTiles(minimized))
+	} else if err := ioutil.WriteFile(*output, []byte(formatTiles(minimized)), 0644); err != nil {
+		fail(ExitCode_INVALID, "%v", err)
+	}
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	. "sigmars-garden-solver/internal/srcs"
+	"sigmars-garden-solver/tui"
+)
+
+func movesMain(args []string) {
+	flags := newFlagSet("moves")
+	maxChecks := flags.Int64("max-checks", 1000000, "checks allowed to label one move")
+	color := flags.Bool("color", true, "use terminal colors")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(int(ExitCode_INVALID))
+	}
+
+	board := loadBoard(flags.Arg(0))
+	moves := NewOracle(*maxChecks).Moves(board)
+	fmt.Print(tui.RenderMoves(&board, moves, *color))
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"image/png"
+	"os"
+	"sigmars-garden-solver/screen"
+	"sigmars-garden-solver/vision"
+)
+
+func parseMain(args []string) {
+	flags := newFlagSet("parse")
+	calibrationPath := flags.String("calibration", "", "calibration of the screenshot resolution (json), the default one of the resolution if empty")
+	overlay := flags.String("overlay", "", "write the recognized tiles over the screenshot in this png")
+	spritesDir := flags.String("sprites", "", "use the sprites of this directory instead of the bundled ones")
+	learn := flags.String("learn", "", "board of the screenshot (json): cut its tiles into the -sprites directory")
+	threshold := flags.Float64("threshold", 0.2, "report tiles recognized with a lower confidence")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		fmt.Printf("%s parse [-calibration calibration.json] [-overlay debug.png] [-sprites dir] screenshot.png\n", os.Args[0])
+		flags.Usage()
+		os.Exit(int(ExitCode_INVALID))
+	}
+
+	f, err := os.Open(flags.Arg(0))
+	if err != nil {
+		fail(ExitCode_INVALID, "%v", err)
+	}
+	img, err := png.Decode(f)
+	f.Close()
+	if err != nil {
+		fail(ExitCode_INVALID, "%s: %v", flags.Arg(0), err)
+	}
+
+	cal := screen.Default(img.Bounds().Dx(), img.Bounds().Dy())
+	if *calibrationPath != "" {
+		if cal, err = screen.LoadCalibration(*calibrationPath); err != nil {
+			fail(ExitCode_INVALID, "%v", err)
+		}
+	}
+	if img.Bounds().Dx() != cal.Width || img.Bounds().Dy() != cal.Height {
+		cal = cal.Scale(img.Bounds().Dx(), img.Bounds().Dy())
+	}
+
+	if *learn != "" {
+		if *spritesDir == "" {
+			fmt.Println("-learn needs a -sprites directory")
+			os.Exit(int(ExitCode_INVALID))
+		}
+		sprites, err := vision.Learn(img, cal, loadTiles(*learn))
+		if err != nil {
+			fail(ExitCode_INVALID, "%v", err)
+		}
+		if err := vision.SaveSprites(*spritesDir, sprites); err != nil {
+			fail(ExitCode_INVALID, "%v", err)
+		}
+		fmt.Println("learnt", len(sprites), "sprites")
+		return
+	}
+
+	sprites := vision.DefaultSprites()
+	if *spritesDir != "" {
+		if sprites, err = vision.LoadSprites(*spritesDir); err != nil {
+			fail(ExitCode_INVALID, "%v", err)
+		}
+	}
+
+	result, err := vision.Parse(img, cal, sprites)
+	if err != nil {
+		fail(ExitCode_INVALID, "%v", err)
+	}
+
+	fmt.Print(formatTiles(result.Tiles))
+
+	for _, pos := range result.LowConfidence(*threshold) {
+		fmt.Fprintf(os.Stderr, "low confidence %.2f for %s at {x:%2d y:%2d}\n", result.Confidence[pos.X][pos.Y], result.Tiles[pos.X][pos.Y], pos.X, pos.Y)
+	}
+
+	if *overlay != "" {
+		out, err := os.Create(*overlay)
+		if err != nil {
+			fail(ExitCode_INVALID, "%v", err)
+		}
+		defer out.Close()
+		if err := png.Encode(out, result.Overlay(img)); err != nil {
+			fail(ExitCode_INVALID, "%v", err)
+		}
+	}
+}
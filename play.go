@@ -0,0 +1,23 @@
+package main
+
+import (
+	"os"
+	"sigmars-garden-solver/tui"
+)
+
+func playMain(args []string) {
+	flags := newFlagSet("play")
+	color := flags.Bool("color", true, "use terminal colors")
+	maxChecks := flags.Int64("max-checks", 1000000, "checks allowed to the solver for a hint")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(int(ExitCode_INVALID))
+	}
+
+	board := loadBoard(flags.Arg(0))
+	if err := tui.Play(board, os.Stdin, os.Stdout, tui.PlayOptions{Color: *color, MaxChecks: *maxChecks}); err != nil {
+		fail(ExitCode_INVALID, "%v", err)
+	}
+}
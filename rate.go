@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	. "sigmars-garden-solver/internal/srcs"
+	"text/tabwriter"
+)
+
+func rateMain(args []string) {
+	flags := newFlagSet("rate")
+	maxChecks := flags.Int64("max-checks", 1000000, "checks allowed to solve a board and to count its solutions")
+	moveChecks := flags.Int64("move-checks", 10000, "checks allowed to tell if a move loses")
+	maxSolutions := flags.Int("max-solutions", 1000, "solutions counted before stopping")
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(int(ExitCode_INVALID))
+	}
+
+	var paths []string
+	for _, arg := range flags.Args() {
+		paths = append(paths, boardFiles(arg)...)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "file\tlevel\tscore\tchecks\tsolutions\tdead first moves\tbranching\tfirst mistake\t")
+	for _, path := range paths {
+		rating, err := ratePath(path, RateOptions{MaxChecks: *maxChecks, MoveChecks: *moveChecks, MaxSolutions: *maxSolutions})
+		if err != nil {
+			fmt.Fprintf(w, "%s\tinvalid: %v\t\t\t\t\t\t\t\n", path, err)
+			continue
+		}
+		solutions := fmt.Sprint(rating.Solutions)
+		if !rating.SolutionsExact {
+			solutions = ">=" + solutions
+		}
+		fmt.Fprintf(w, "%s\t%s\t%.1f\t%d\t%s\t%d/%d\t%.2f\t%d\t\n", path, rating.Level, rating.Score, rating.Checks,
+			solutions, rating.DeadFirstMoves, rating.FirstMoves, rating.BranchingFactor, rating.FirstMistake)
+	}
+	w.Flush()
+}
+
+// boardFiles returns the json files of a directory, or the path itself if it
+// is not a directory.
+func boardFiles(path string) []string {
+	infos, err := ioutil.ReadDir(path)
+	if err != nil {
+		return []string{path}
+	}
+	var paths []string
+	for _, info := range infos {
+		if !info.IsDir() && filepath.Ext(info.Name()) == ".json" {
+			paths = append(paths, filepath.Join(path, info.Name()))
+		}
+	}
+	return paths
+}
+
+func ratePath(path string, opts RateOptions) (Rating, error) {
+	board, err := readBoard(path)
+	if err != nil {
+		return Rating{}, err
+	}
+	return Rate(board, opts), nil
+}
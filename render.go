@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	. "sigmars-garden-solver/internal/srcs"
+	"sigmars-garden-solver/render"
+)
+
+func renderMain(args []string) {
+	flags := newFlagSet("render")
+	output := flags.String("o", "board.svg", "output file, .svg or .png, - for an SVG on the standard output")
+	size := flags.Float64("size", render.DefaultHexSize, "radius of a tile in pixels")
+	solution := flags.Bool("solution", false, "solve the board and draw the numbered moves")
+	flags.Parse(args)
+
+	board := loadBoard(inputArg(flags))
+	opts := render.Options{HexSize: *size}
+
+	if *solution {
+		solved := board.Clone()
+		actions, _, _, _ := solved.SolveWithOptions(SolveOptions{Quiet: true})
+		if len(actions) == 0 {
+			fmt.Fprintln(os.Stderr, "no solution found")
+		}
+		opts.Actions = actions
+	}
+
+	f := os.Stdout
+	if *output != "-" {
+		var err error
+		if f, err = os.Create(*output); err != nil {
+			fail(ExitCode_INVALID, "%v", err)
+		}
+		defer f.Close()
+	}
+
+	var err error
+	switch filepath.Ext(*output) {
+	case ".png":
+		err = render.PNG(f, &board, opts)
+	default:
+		err = render.SVG(f, &board, opts)
+	}
+	if err != nil {
+		fail(ExitCode_INVALID, "%v", err)
+	}
+}
@@ -0,0 +1,29 @@
+package render
+
+// glyphs is a 3x5 bitmap font holding the characters used by the PNG
+// renderer, each row is 3 bits wide, the most significant bit on the left.
+var glyphs = map[rune][5]uint8{
+	'0': {0b111, 0b101, 0b101, 0b101, 0b111},
+	'1': {0b010, 0b110, 0b010, 0b010, 0b111},
+	'2': {0b111, 0b001, 0b111, 0b100, 0b111},
+	'3': {0b111, 0b001, 0b011, 0b001, 0b111},
+	'4': {0b101, 0b101, 0b111, 0b001, 0b001},
+	'5': {0b111, 0b100, 0b111, 0b001, 0b111},
+	'6': {0b111, 0b100, 0b111, 0b101, 0b111},
+	'7': {0b111, 0b001, 0b010, 0b010, 0b010},
+	'8': {0b111, 0b101, 0b111, 0b101, 0b111},
+	'9': {0b111, 0b101, 0b111, 0b001, 0b111},
+	'B': {0b110, 0b101, 0b110, 0b101, 0b110},
+	'C': {0b111, 0b100, 0b100, 0b100, 0b111},
+	'D': {0b110, 0b101, 0b101, 0b101, 0b110},
+	'G': {0b111, 0b100, 0b101, 0b101, 0b111},
+	'K': {0b101, 0b101, 0b110, 0b101, 0b101},
+	'L': {0b100, 0b100, 0b100, 0b100, 0b111},
+	'O': {0b010, 0b101, 0b101, 0b101, 0b010},
+	'W': {0b101, 0b101, 0b101, 0b111, 0b101},
+}
+
+const (
+	glyphWidth  = 3
+	glyphHeight = 5
+)
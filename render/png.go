@@ -0,0 +1,169 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+	"strconv"
+
+	. "sigmars-garden-solver/internal/srcs"
+)
+
+// PNG writes the board as a PNG image, see SVG for what is drawn.
+func PNG(w io.Writer, board *Board, opts Options) error {
+	return png.Encode(w, Image(board, opts))
+}
+
+// Image draws the board with the standard image packages.
+func Image(board *Board, opts Options) *image.RGBA {
+	l := newLayout(opts)
+	img := image.NewRGBA(image.Rect(0, 0, l.width, l.height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{backgroundColor}, image.Point{}, draw.Src)
+
+	forEachTile(board, func(x, y int, tile Tile) {
+		fill := TileColor(tile.Type)
+		if tile.Lock {
+			fill = lockedColor(fill)
+		}
+		fillPolygon(img, l.corners(x, y, 1), outlineColor)
+		fillPolygon(img, l.corners(x, y, 0.9), fill)
+
+		if label := TileLabel(tile.Type); label != "" {
+			cx, cy := l.center(x, y)
+			DrawText(img, label, cx, cy, l.size/8, labelColor(fill))
+		}
+	})
+
+	for _, pos := range opts.Marked {
+		corners := l.corners(pos.X, pos.Y, 0.9)
+		for i := range corners {
+			next := corners[(i+1)%len(corners)]
+			drawLine(img, corners[i][0], corners[i][1], next[0], next[1], l.size/8, markColor)
+		}
+	}
+
+	for i, action := range opts.Actions {
+		x1, y1 := l.center(action.X1, action.Y1)
+		x2, y2 := l.center(action.X2, action.Y2)
+		mx, my := (x1+x2)/2, (y1+y2)/2
+
+		if action.X1 != action.X2 || action.Y1 != action.Y2 {
+			dx, dy := x2-x1, y2-y1
+			dist := math.Hypot(dx, dy)
+			ux, uy := dx/dist, dy/dist
+			x2, y2 = x2-ux*l.size*0.6, y2-uy*l.size*0.6
+			drawLine(img, x1, y1, x2, y2, l.size/8, arrowColor)
+
+			head := l.size * 0.4
+			fillPolygon(img, [][2]float64{
+				{x2, y2},
+				{x2 - ux*head - uy*head/2, y2 - uy*head + ux*head/2},
+				{x2 - ux*head + uy*head/2, y2 - uy*head - ux*head/2},
+			}, arrowColor)
+		}
+		fillCircle(img, mx, my, l.size*0.4, arrowColor)
+		DrawText(img, strconv.Itoa(i+1), mx, my, l.size/12, color.RGBA{0xff, 0xff, 0xff, 0xff})
+	}
+
+	return img
+}
+
+// fillPolygon fills a convex or concave polygon with the even-odd rule,
+// testing the center of every pixel of its bounding box.
+func fillPolygon(img *image.RGBA, points [][2]float64, c color.RGBA) {
+	minX, minY, maxX, maxY := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	for _, p := range points {
+		minX, maxX = math.Min(minX, p[0]), math.Max(maxX, p[0])
+		minY, maxY = math.Min(minY, p[1]), math.Max(maxY, p[1])
+	}
+
+	for py := int(math.Floor(minY)); py <= int(math.Ceil(maxY)); py++ {
+		for px := int(math.Floor(minX)); px <= int(math.Ceil(maxX)); px++ {
+			if insidePolygon(points, float64(px)+0.5, float64(py)+0.5) {
+				blend(img, px, py, c)
+			}
+		}
+	}
+}
+
+func insidePolygon(points [][2]float64, x, y float64) bool {
+	inside := false
+	for i, j := 0, len(points)-1; i < len(points); j, i = i, i+1 {
+		pi, pj := points[i], points[j]
+		if (pi[1] > y) != (pj[1] > y) && x < (pj[0]-pi[0])*(y-pi[1])/(pj[1]-pi[1])+pi[0] {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+func fillCircle(img *image.RGBA, cx, cy, r float64, c color.RGBA) {
+	for py := int(cy - r); py <= int(cy+r)+1; py++ {
+		for px := int(cx - r); px <= int(cx+r)+1; px++ {
+			if math.Hypot(float64(px)+0.5-cx, float64(py)+0.5-cy) <= r {
+				blend(img, px, py, c)
+			}
+		}
+	}
+}
+
+// drawLine draws a segment of the given width as a filled quadrilateral.
+func drawLine(img *image.RGBA, x1, y1, x2, y2, width float64, c color.RGBA) {
+	dist := math.Hypot(x2-x1, y2-y1)
+	if dist == 0 {
+		return
+	}
+	nx, ny := -(y2-y1)/dist*width/2, (x2-x1)/dist*width/2
+	fillPolygon(img, [][2]float64{
+		{x1 + nx, y1 + ny},
+		{x2 + nx, y2 + ny},
+		{x2 - nx, y2 - ny},
+		{x1 - nx, y1 - ny},
+	}, c)
+}
+
+// DrawText writes text centered on (cx, cy) with the bitmap font, each font
+// pixel being a square of side scale.
+func DrawText(img *image.RGBA, text string, cx, cy, scale float64, c color.RGBA) {
+	if scale < 1 {
+		scale = 1
+	}
+	width := float64(len(text)*(glyphWidth+1)-1) * scale
+	left, top := cx-width/2, cy-glyphHeight*scale/2
+
+	for i, r := range text {
+		glyph := glyphs[r]
+		for row := 0; row < glyphHeight; row++ {
+			for col := 0; col < glyphWidth; col++ {
+				if glyph[row]>>(glyphWidth-1-col)&1 == 0 {
+					continue
+				}
+				x0 := left + float64(i*(glyphWidth+1)+col)*scale
+				y0 := top + float64(row)*scale
+				for py := int(math.Round(y0)); py < int(math.Round(y0+scale)); py++ {
+					for px := int(math.Round(x0)); px < int(math.Round(x0+scale)); px++ {
+						blend(img, px, py, c)
+					}
+				}
+			}
+		}
+	}
+}
+
+// blend draws c over the pixel, using the alpha of c.
+func blend(img *image.RGBA, x, y int, c color.RGBA) {
+	if !(image.Point{x, y}.In(img.Bounds())) {
+		return
+	}
+	if c.A == 0xff {
+		img.SetRGBA(x, y, c)
+		return
+	}
+	old := img.RGBAAt(x, y)
+	a := uint16(c.A)
+	mix := func(n, o uint8) uint8 { return uint8((uint16(n)*a + uint16(o)*(0xff-a)) / 0xff) }
+	img.SetRGBA(x, y, color.RGBA{mix(c.R, old.R), mix(c.G, old.G), mix(c.B, old.B), 0xff})
+}
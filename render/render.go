@@ -0,0 +1,129 @@
+// Package render draws boards and solutions as SVG or PNG images.
+package render
+
+import (
+	"image/color"
+	"math"
+
+	. "sigmars-garden-solver/internal/srcs"
+)
+
+type Options struct {
+	HexSize float64    // radius of a tile in pixels, DefaultHexSize if 0
+	Actions []Action   // solution drawn as numbered arrows, in order
+	Marked  []Position // tiles outlined, such as the tiles of a dead end
+}
+
+const DefaultHexSize = 24
+
+var tileColors = map[TileType]color.RGBA{
+	TileType_EMPTY:  {0x3a, 0x33, 0x2b, 0xff},
+	TileType_WHITE:  {0xe8, 0xe4, 0xd8, 0xff},
+	TileType_CYAN:   {0x6f, 0xc9, 0xd6, 0xff},
+	TileType_ORANGE: {0xe0, 0x6a, 0x2b, 0xff},
+	TileType_BLUE:   {0x3b, 0x6f, 0xd1, 0xff},
+	TileType_GREEN:  {0x4f, 0xa8, 0x3d, 0xff},
+	TileType_LIGHT:  {0xf3, 0xdf, 0x7a, 0xff},
+	TileType_DARK:   {0x5c, 0x3f, 0x6e, 0xff},
+	TileType_KEY:    {0xb8, 0xc0, 0xc8, 0xff},
+	TileType_L1:     {0x6b, 0x6e, 0x75, 0xff},
+	TileType_L2:     {0x9a, 0x9c, 0x94, 0xff},
+	TileType_L3:     {0x8c, 0x5a, 0x44, 0xff},
+	TileType_L4:     {0xc0, 0x7a, 0x3e, 0xff},
+	TileType_L5:     {0xd8, 0xd8, 0xe0, 0xff},
+	TileType_L6:     {0xf0, 0xc0, 0x30, 0xff},
+}
+
+var tileLabels = map[TileType]string{
+	TileType_WHITE:  "W",
+	TileType_CYAN:   "C",
+	TileType_ORANGE: "O",
+	TileType_BLUE:   "B",
+	TileType_GREEN:  "G",
+	TileType_LIGHT:  "L",
+	TileType_DARK:   "D",
+	TileType_KEY:    "K",
+	TileType_L1:     "1",
+	TileType_L2:     "2",
+	TileType_L3:     "3",
+	TileType_L4:     "4",
+	TileType_L5:     "5",
+	TileType_L6:     "6",
+}
+
+var (
+	backgroundColor = color.RGBA{0x1e, 0x1a, 0x16, 0xff}
+	outlineColor    = color.RGBA{0x14, 0x11, 0x0e, 0xff}
+	arrowColor      = color.RGBA{0xff, 0x30, 0x60, 0xff}
+	markColor       = color.RGBA{0xff, 0x20, 0x20, 0xff}
+)
+
+// TileColor returns the fill color of a tile type.
+func TileColor(tileType TileType) color.RGBA {
+	return tileColors[tileType]
+}
+
+// TileLabel returns the symbol written on a tile type.
+func TileLabel(tileType TileType) string {
+	return tileLabels[tileType]
+}
+
+// labelColor picks a label color readable on a tile of color c.
+func labelColor(c color.RGBA) color.RGBA {
+	if int(c.R)*299+int(c.G)*587+int(c.B)*114 > 128*1000 {
+		return color.RGBA{0x10, 0x10, 0x10, 0xff}
+	}
+	return color.RGBA{0xf0, 0xf0, 0xf0, 0xff}
+}
+
+// lockedColor darkens the color of locked tiles.
+func lockedColor(c color.RGBA) color.RGBA {
+	return color.RGBA{c.R / 3, c.G / 3, c.B / 3, c.A}
+}
+
+type layout struct {
+	size          float64
+	width, height int
+	cx, cy        float64
+}
+
+func newLayout(opts Options) layout {
+	size := opts.HexSize
+	if size <= 0 {
+		size = DefaultHexSize
+	}
+	margin := size
+	width := 11*math.Sqrt(3)*size + 2*margin
+	height := (15+2)*size + 2*margin
+	return layout{
+		size:   size,
+		width:  int(math.Ceil(width)),
+		height: int(math.Ceil(height)),
+		cx:     width / 2,
+		cy:     height / 2,
+	}
+}
+
+// center returns the pixel coordinates of the center of the tile (x, y).
+func (this layout) center(x, y int) (float64, float64) {
+	hx, hy := HexCenter(x, y)
+	return this.cx + hx*this.size, this.cy + hy*this.size
+}
+
+// corners returns the 6 corners of a pointy-top hexagon.
+func (this layout) corners(x, y int, scale float64) [][2]float64 {
+	cx, cy := this.center(x, y)
+	corners := make([][2]float64, 6)
+	for i := range corners {
+		angle := math.Pi/180*float64(60*i) - math.Pi/2
+		corners[i] = [2]float64{cx + this.size*scale*math.Cos(angle), cy + this.size*scale*math.Sin(angle)}
+	}
+	return corners
+}
+
+func forEachTile(board *Board, f func(x, y int, tile Tile)) {
+	for i, tile := range board.Board {
+		x, y := ToXYPos(i)
+		f(x, y, tile)
+	}
+}
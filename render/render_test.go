@@ -0,0 +1,90 @@
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"testing"
+
+	. "sigmars-garden-solver/internal/srcs"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files of testdata/")
+
+func loadBoard(t *testing.T, path string) Board {
+	t.Helper()
+	byteValue, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tiles [][]TileType
+	if err := json.Unmarshal(byteValue, &tiles); err != nil {
+		t.Fatal(err)
+	}
+	board, err := ParseBoard(tiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return board
+}
+
+// the board of input1 with its solution, rewritten with -update
+func TestSVGGolden(t *testing.T) {
+	board := loadBoard(t, "../inputs/input1.json")
+	solved := board.Clone()
+	actions, _, _, err := solved.SolveWithOptions(SolveOptions{Quiet: true})
+	if err != nil || len(actions) == 0 {
+		t.Fatalf("input1 not solved: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := SVG(&buf, &board, Options{Actions: actions, Marked: []Position{{X: 0, Y: 0}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	golden := "testdata/input1.svg"
+	if *update {
+		if err := ioutil.WriteFile(golden, buf.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := ioutil.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("SVG of input1 differs from %s, rerun with -update after a deliberate change", golden)
+	}
+}
+
+// the pixels left of the label of each tile have the color of the tile,
+// darkened if it is locked
+func TestPNGColors(t *testing.T) {
+	board := loadBoard(t, "../inputs/input1.json")
+	var buf bytes.Buffer
+	if err := PNG(&buf, &board, Options{HexSize: 30}); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l := newLayout(Options{HexSize: 30})
+	if size := img.Bounds().Size(); size.X != l.width || size.Y != l.height {
+		t.Fatalf("size %v, want %dx%d", size, l.width, l.height)
+	}
+	forEachTile(&board, func(x, y int, tile Tile) {
+		want := TileColor(tile.Type)
+		if tile.Lock {
+			want = lockedColor(want)
+		}
+		cx, cy := l.center(x, y)
+		got := color.RGBAModel.Convert(img.At(int(cx-0.5*l.size), int(cy))).(color.RGBA)
+		if got != want {
+			t.Errorf("%s (%d,%d): pixel %v, want %v", tile.Type, x, y, got, want)
+		}
+	})
+}
@@ -0,0 +1,77 @@
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"strings"
+
+	. "sigmars-garden-solver/internal/srcs"
+)
+
+// SVG writes the board as an SVG image. Locked tiles are shaded, the tiles of
+// opts.Marked are outlined and, if opts.Actions is set, each action is drawn
+// as an arrow numbered by its position in the solution.
+func SVG(w io.Writer, board *Board, opts Options) error {
+	l := newLayout(opts)
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", l.width, l.height, l.width, l.height)
+	fmt.Fprintf(bw, `<defs><marker id="arrow" viewBox="0 0 10 10" refX="9" refY="5" markerWidth="5" markerHeight="5" orient="auto-start-reverse"><path d="M0,0 L10,5 L0,10 z" fill="%s"/></marker></defs>`+"\n", hexColor(arrowColor))
+	fmt.Fprintf(bw, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", hexColor(backgroundColor))
+
+	forEachTile(board, func(x, y int, tile Tile) {
+		fill := TileColor(tile.Type)
+		if tile.Lock {
+			fill = lockedColor(fill)
+		}
+		var points []string
+		for _, c := range l.corners(x, y, 0.95) {
+			points = append(points, fmt.Sprintf("%.1f,%.1f", c[0], c[1]))
+		}
+		fmt.Fprintf(bw, `<polygon points="%s" fill="%s" stroke="%s" stroke-width="1"><title>(%d,%d) %s</title></polygon>`+"\n",
+			strings.Join(points, " "), hexColor(fill), hexColor(outlineColor), x, y, tile.Type)
+
+		if label := TileLabel(tile.Type); label != "" {
+			cx, cy := l.center(x, y)
+			fmt.Fprintf(bw, `<text x="%.1f" y="%.1f" font-family="sans-serif" font-size="%.1f" font-weight="bold" text-anchor="middle" dominant-baseline="central" fill="%s">%s</text>`+"\n",
+				cx, cy, l.size*0.8, hexColor(labelColor(fill)), label)
+		}
+	})
+
+	for _, pos := range opts.Marked {
+		var points []string
+		for _, c := range l.corners(pos.X, pos.Y, 0.85) {
+			points = append(points, fmt.Sprintf("%.1f,%.1f", c[0], c[1]))
+		}
+		fmt.Fprintf(bw, `<polygon points="%s" fill="none" stroke="%s" stroke-width="%.1f"/>`+"\n",
+			strings.Join(points, " "), hexColor(markColor), l.size/8)
+	}
+
+	for i, action := range opts.Actions {
+		x1, y1 := l.center(action.X1, action.Y1)
+		x2, y2 := l.center(action.X2, action.Y2)
+		mx, my := (x1+x2)/2, (y1+y2)/2
+
+		if action.X1 != action.X2 || action.Y1 != action.Y2 {
+			// stop the arrow on the border of the second tile
+			dx, dy := x2-x1, y2-y1
+			dist := math.Hypot(dx, dy)
+			x2, y2 = x2-dx/dist*l.size*0.6, y2-dy/dist*l.size*0.6
+			fmt.Fprintf(bw, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s" stroke-width="%.1f" stroke-opacity="0.8" marker-end="url(#arrow)"/>`+"\n",
+				x1, y1, x2, y2, hexColor(arrowColor), l.size/8)
+		}
+		fmt.Fprintf(bw, `<circle cx="%.1f" cy="%.1f" r="%.1f" fill="%s"/>`+"\n", mx, my, l.size*0.4, hexColor(arrowColor))
+		fmt.Fprintf(bw, `<text x="%.1f" y="%.1f" font-family="sans-serif" font-size="%.1f" text-anchor="middle" dominant-baseline="central" fill="#ffffff">%d</text>`+"\n",
+			mx, my, l.size*0.45, i+1)
+	}
+
+	fmt.Fprintln(bw, "</svg>")
+	return bw.Flush()
+}
+
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
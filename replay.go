@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	. "sigmars-garden-solver/internal/srcs"
+	"sigmars-garden-solver/tui"
+)
+
+func replayMain(args []string) {
+	flags := newFlagSet("replay")
+	delay := flags.Duration("delay", 0, "advance automatically after this delay instead of waiting for a key")
+	color := flags.Bool("color", true, "use terminal colors")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(int(ExitCode_INVALID))
+	}
+
+	board := loadBoard(flags.Arg(0))
+	solved := board.Clone()
+	actions, _, _, _ := solved.SolveWithOptions(SolveOptions{Quiet: true})
+	if len(actions) == 0 {
+		fmt.Println("no solution found")
+		os.Exit(int(ExitCode_UNSOLVABLE))
+	}
+
+	if err := tui.Replay(board, actions, os.Stdin, os.Stdout, tui.ReplayOptions{Delay: *delay, Color: *color}); err != nil {
+		fail(ExitCode_INVALID, "%v", err)
+	}
+}
@@ -0,0 +1,96 @@
+// Package screen maps board positions to pixels of a screen or screenshot.
+package screen
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+
+	. "sigmars-garden-solver/internal/srcs"
+)
+
+// Calibration describes where the board is drawn for a given resolution:
+// Origin is the pixel at the center of the middle tile (5, 5) and HexSize the
+// radius of a tile in pixels.
+type Calibration struct {
+	Width   int     `json:"width"`
+	Height  int     `json:"height"`
+	OriginX float64 `json:"originX"`
+	OriginY float64 `json:"originY"`
+	HexSize float64 `json:"hexSize"`
+}
+
+func LoadCalibration(path string) (Calibration, error) {
+	var cal Calibration
+
+	byteValue, err := ioutil.ReadFile(path)
+	if err != nil {
+		return cal, err
+	}
+	if err := json.Unmarshal(byteValue, &cal); err != nil {
+		return cal, err
+	}
+	return cal, cal.Valid()
+}
+
+func (this Calibration) Valid() error {
+	if this.HexSize <= 0 {
+		return fmt.Errorf("calibration: hexSize must be positive (got %g)", this.HexSize)
+	}
+	if this.Width <= 0 || this.Height <= 0 {
+		return fmt.Errorf("calibration: invalid resolution %dx%d", this.Width, this.Height)
+	}
+	return nil
+}
+
+// Center returns the pixel at the center of the tile (x, y).
+func (this Calibration) Center(x, y int) (float64, float64) {
+	hx, hy := HexCenter(x, y)
+	return this.OriginX + hx*this.HexSize, this.OriginY + hy*this.HexSize
+}
+
+// Pixel returns Center rounded to the nearest pixel.
+func (this Calibration) Pixel(x, y int) (int, int) {
+	px, py := this.Center(x, y)
+	return int(math.Round(px)), int(math.Round(py))
+}
+
+// Scale adapts a calibration measured at one resolution to another one,
+// assuming the board stays horizontally centered and scales with the height.
+func (this Calibration) Scale(width, height int) Calibration {
+	ratio := float64(height) / float64(this.Height)
+	return Calibration{
+		Width:   width,
+		Height:  height,
+		OriginX: float64(width)/2 + (this.OriginX-float64(this.Width)/2)*ratio,
+		OriginY: this.OriginY * ratio,
+		HexSize: this.HexSize * ratio,
+	}
+}
+
+// reference is the calibration of the game full screen at 1920x1080, the
+// board at the center. It is a guess, not measured on the game.
+var reference = Calibration{Width: 1920, Height: 1080, OriginX: 960, OriginY: 540, HexSize: 33}
+
+// Defaults are the calibrations of common resolutions, scaled from the
+// reference; a calibration measured on the screen is more precise.
+var Defaults = []Calibration{
+	{Width: 1280, Height: 720, OriginX: 640, OriginY: 360, HexSize: 22},
+	{Width: 1366, Height: 768, OriginX: 683, OriginY: 384, HexSize: 23.4667},
+	{Width: 1600, Height: 900, OriginX: 800, OriginY: 450, HexSize: 27.5},
+	reference,
+	{Width: 2560, Height: 1440, OriginX: 1280, OriginY: 720, HexSize: 44},
+	{Width: 3840, Height: 2160, OriginX: 1920, OriginY: 1080, HexSize: 66},
+}
+
+// Default returns the calibration of Defaults for the resolution, or the
+// reference scaled to it.
+func Default(width, height int) Calibration {
+	for _, cal := range Defaults {
+		if cal.Width == width && cal.Height == height {
+			return cal
+		}
+	}
+	return reference.Scale(width, height)
+}
@@ -0,0 +1,21 @@
+package screen
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDefaults(t *testing.T) {
+	for _, cal := range Defaults {
+		scaled := reference.Scale(cal.Width, cal.Height)
+		if math.Abs(cal.OriginX-scaled.OriginX) > 0.01 || math.Abs(cal.OriginY-scaled.OriginY) > 0.01 || math.Abs(cal.HexSize-scaled.HexSize) > 0.01 {
+			t.Errorf("%dx%d: %+v, scaled reference %+v", cal.Width, cal.Height, cal, scaled)
+		}
+		if err := cal.Valid(); err != nil {
+			t.Error(err)
+		}
+	}
+	if cal := Default(1920, 1200); cal.Width != 1920 || cal.Height != 1200 || cal.OriginX != 960 {
+		t.Errorf("1920x1200: %+v", cal)
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"sigmars-garden-solver/jobs"
+	"sigmars-garden-solver/server"
+	"time"
+)
+
+func serveMain(args []string) {
+	flags := newFlagSet("serve")
+	addr := flags.String("addr", "localhost:8080", "address to listen on")
+	timeout := flags.Duration("timeout", 30*time.Second, "default time allowed to a request")
+	maxTimeout := flags.Duration("max-timeout", time.Minute, "longest time a request can ask for")
+	concurrency := flags.Int("concurrency", 4, "requests computed at the same time")
+	maxChecks := flags.Int64("max-checks", 0, "checks allowed to a search, 0 for no limit but the timeout")
+	workers := flags.Int("workers", 2, "background jobs solved at the same time")
+	jobTTL := flags.Duration("job-ttl", 10*time.Minute, "time the result of a background job is kept")
+	ui := flags.Bool("ui", false, "serve the board editor at /")
+	flags.Parse(args)
+
+	if flags.NArg() != 0 {
+		flags.Usage()
+		os.Exit(int(ExitCode_INVALID))
+	}
+
+	handler := server.New(server.Options{
+		Timeout:       *timeout,
+		MaxTimeout:    *maxTimeout,
+		MaxConcurrent: *concurrency,
+		MaxChecks:     *maxChecks,
+		Jobs:          jobs.Options{Workers: *workers, TTL: *jobTTL, MaxChecks: *maxChecks},
+		UI:            *ui,
+	})
+	if *ui {
+		fmt.Printf("board editor on http://%s/\n", *addr)
+	}
+	fmt.Println("listening on", *addr)
+	err := http.ListenAndServe(*addr, handler)
+	handler.Close()
+	fail(ExitCode_INVALID, "%v", err)
+}
@@ -0,0 +1,98 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"sigmars-garden-solver/api"
+	"sigmars-garden-solver/jobs"
+)
+
+type JobResponse struct {
+	ID        string             `json:"id"`
+	Status    jobs.Status        `json:"status"`
+	Checks    int64              `json:"checks"`
+	Depth     int                `json:"depth"`
+	Remaining int                `json:"remaining"`
+	Error     string             `json:"error,omitempty"`
+	Result    *api.SolveResponse `json:"result,omitempty"`
+	Submitted time.Time          `json:"submitted"`
+}
+
+func newJobResponse(job jobs.Job) JobResponse {
+	response := JobResponse{
+		ID:        job.ID,
+		Status:    job.Status,
+		Checks:    job.Progress.Checks,
+		Depth:     job.Progress.Depth,
+		Remaining: job.Progress.Remaining,
+		Error:     job.Error,
+		Submitted: job.Submitted,
+	}
+	if job.Result != nil {
+		result := api.NewSolveResponse(job.Result.Actions, job.Result.Checks, job.Result.Duration)
+		response.Result = &result
+	}
+	return response
+}
+
+// handleJobs serves the solves too long for a request:
+//   - POST /jobs queues a board and returns the job,
+//   - GET /jobs lists the jobs kept,
+//   - GET /jobs/<id> returns the job, its progress and its result when done,
+//   - DELETE /jobs/<id> cancels it.
+func (this *Server) handleJobs(w http.ResponseWriter, r *http.Request) {
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, "/jobs"), "/")
+
+	switch {
+	case id == "" && r.Method == http.MethodPost:
+		req, err := this.decode(w, r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		board, err := api.ParseBoard(req.Board)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		id, submitErr := this.jobs.Submit(board)
+		if submitErr != nil {
+			writeError(w, api.NewError(http.StatusServiceUnavailable, "busy", "%v", submitErr))
+			return
+		}
+		job, _ := this.jobs.Get(id)
+		writeJSON(w, http.StatusAccepted, newJobResponse(job))
+	case id == "" && r.Method == http.MethodGet:
+		list := []JobResponse{}
+		for _, job := range this.jobs.List() {
+			list = append(list, newJobResponse(job))
+		}
+		writeJSON(w, http.StatusOK, list)
+	case id != "" && (r.Method == http.MethodGet || r.Method == http.MethodDelete):
+		if r.Method == http.MethodDelete {
+			if err := this.jobs.Cancel(id); err != nil {
+				writeJobError(w, id, err)
+				return
+			}
+		}
+		job, err := this.jobs.Get(id)
+		if err != nil {
+			writeJobError(w, id, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, newJobResponse(job))
+	default:
+		writeError(w, api.NewError(http.StatusMethodNotAllowed, "method_not_allowed", "%s is not allowed on %s", r.Method, r.URL.Path))
+	}
+}
+
+func writeJobError(w http.ResponseWriter, id string, err error) {
+	if errors.Is(err, jobs.ErrNotFound) {
+		writeError(w, api.NewError(http.StatusNotFound, "job_not_found", "no job %s, it may have expired", id))
+		return
+	}
+	writeError(w, api.NewError(http.StatusInternalServerError, "internal", "%v", err))
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"sigmars-garden-solver/api"
+	. "sigmars-garden-solver/internal/srcs"
+)
+
+func (this *Server) solve(ctx context.Context, req Request) (any, *api.Error) {
+	board, err := api.ParseBoard(req.Board)
+	if err != nil {
+		return nil, err
+	}
+	return api.Solve(ctx, board, this.opts.MaxChecks)
+}
+
+// validate reports the problems a board has without searching it: its shape
+// and types are answered as errors, its inventory as problems.
+func (this *Server) validate(ctx context.Context, req Request) (any, *api.Error) {
+	board, err := api.ParseBoard(req.Board)
+	if err != nil {
+		return nil, err
+	}
+	return api.Validate(board), nil
+}
+
+func (this *Server) hint(ctx context.Context, req Request) (any, *api.Error) {
+	board, err := api.ParseBoard(req.Board)
+	if err != nil {
+		return nil, err
+	}
+	return api.Hint(ctx, board, this.opts.MaxChecks)
+}
+
+func (this *Server) generate(ctx context.Context, req Request) (any, *api.Error) {
+	seed := time.Now().UnixNano()
+	if req.Seed != nil {
+		seed = *req.Seed
+	}
+
+	board, err := Generate(seed, GenerateOptions{Context: ctx})
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, api.SearchError(ctx.Err())
+		}
+		return nil, api.NewError(http.StatusInternalServerError, "generate_failed", "%v", err)
+	}
+	return api.GenerateResponse{Seed: seed, Board: board.Tiles()}, nil
+}
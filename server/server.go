@@ -0,0 +1,178 @@
+// Package server exposes the solver as a JSON API over HTTP.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"sigmars-garden-solver/api"
+	. "sigmars-garden-solver/internal/srcs"
+	"sigmars-garden-solver/jobs"
+)
+
+type Options struct {
+	Timeout       time.Duration // default time allowed to a request, 30s if 0
+	MaxTimeout    time.Duration // longest time a request can ask for, 1m if 0
+	MaxConcurrent int           // requests computed at the same time, 4 if 0
+	MaxChecks     int64         // checks allowed to a search, 0 for no limit but the timeout
+	MaxBodySize   int64         // bytes read from a request body, 1MB if 0
+	Jobs          jobs.Options  // background solves of /jobs, MaxChecks included
+	UI            bool          // serve the board editor at /
+}
+
+type Server struct {
+	opts  Options
+	slots chan struct{} // one per request being computed
+	mux   *http.ServeMux
+	jobs  *jobs.Manager
+}
+
+// New returns a server with these routes, all taking and returning JSON:
+//   - POST /solve: the solution of a board,
+//   - POST /validate: the problems found in a board without searching it,
+//   - POST /hint: a move that keeps the board solvable,
+//   - POST /generate: a new solvable board,
+//   - /jobs: the solves run in the background, see handleJobs,
+//   - GET /: the board editor, with Options.UI.
+//
+// Errors are returned as {"error": {"code": ..., "message": ...}}.
+func New(opts Options) *Server {
+	if opts.Timeout <= 0 {
+		opts.Timeout = 30 * time.Second
+	}
+	if opts.MaxTimeout <= 0 {
+		opts.MaxTimeout = time.Minute
+	}
+	if opts.MaxConcurrent <= 0 {
+		opts.MaxConcurrent = 4
+	}
+	if opts.MaxBodySize <= 0 {
+		opts.MaxBodySize = 1 << 20
+	}
+
+	this := &Server{
+		opts:  opts,
+		slots: make(chan struct{}, opts.MaxConcurrent),
+		mux:   http.NewServeMux(),
+		jobs:  jobs.NewManager(opts.Jobs),
+	}
+	this.mux.HandleFunc("/solve", this.handle(this.solve))
+	this.mux.HandleFunc("/validate", this.handle(this.validate))
+	this.mux.HandleFunc("/hint", this.handle(this.hint))
+	this.mux.HandleFunc("/generate", this.handle(this.generate))
+	this.mux.HandleFunc("/jobs", this.handleJobs)
+	this.mux.HandleFunc("/jobs/", this.handleJobs)
+	if opts.UI {
+		this.mux.HandleFunc("/", this.handleUI)
+	} else {
+		this.mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+			writeError(w, api.NewError(http.StatusNotFound, "not_found", "no route %s", r.URL.Path))
+		})
+	}
+	return this
+}
+
+// Close cancels the jobs still running.
+func (this *Server) Close() {
+	this.jobs.Close()
+}
+
+func (this *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	this.mux.ServeHTTP(w, r)
+}
+
+// Request is the body of every route. The board may also be sent alone, as
+// the array of lines NewBoard accepts.
+type Request struct {
+	Board   [][]TileType `json:"board,omitempty"`
+	Timeout string       `json:"timeout,omitempty"` // as parsed by time.ParseDuration, capped to Options.MaxTimeout
+	Seed    *int64       `json:"seed,omitempty"`    // generate only, random if not set
+}
+
+type handler func(ctx context.Context, req Request) (any, *api.Error)
+
+// handle decodes the request, waits for a free slot and computes the
+// response within the timeout of the request. Panics are answered as
+// internal errors.
+func (this *Server) handle(h handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if p := recover(); p != nil {
+				writeError(w, api.NewError(http.StatusInternalServerError, "internal", "%v", p))
+			}
+		}()
+
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeError(w, api.NewError(http.StatusMethodNotAllowed, "method_not_allowed", "use POST"))
+			return
+		}
+		req, err := this.decode(w, r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		timeout := this.opts.Timeout
+		if req.Timeout != "" {
+			d, parseErr := time.ParseDuration(req.Timeout)
+			if parseErr != nil || d <= 0 {
+				writeError(w, api.NewError(http.StatusBadRequest, "invalid_timeout", "timeout %q is not a positive duration", req.Timeout))
+				return
+			}
+			timeout = d
+		}
+		if timeout > this.opts.MaxTimeout {
+			timeout = this.opts.MaxTimeout
+		}
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		select {
+		case this.slots <- struct{}{}:
+			defer func() { <-this.slots }()
+		case <-ctx.Done():
+			writeError(w, api.NewError(http.StatusServiceUnavailable, "busy", "no slot freed within %s", timeout))
+			return
+		}
+
+		response, err := h(ctx, req)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, response)
+	}
+}
+
+func (this *Server) decode(w http.ResponseWriter, r *http.Request) (Request, *api.Error) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, this.opts.MaxBodySize))
+	if err != nil {
+		return Request{}, api.NewError(http.StatusRequestEntityTooLarge, "body_too_large", "%v", err)
+	}
+
+	var req Request
+	if len(body) == 0 {
+		return req, nil
+	}
+	if err := json.Unmarshal(body, &req.Board); err == nil {
+		return req, nil
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		return Request{}, api.NewError(http.StatusBadRequest, "invalid_json", "%v", err)
+	}
+	return req, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, err *api.Error) {
+	writeJSON(w, err.Status, api.ErrorResponse(err))
+}
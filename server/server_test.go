@@ -0,0 +1,223 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"sigmars-garden-solver/api"
+)
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	byteValue, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(byteValue)
+}
+
+// post sends the body to the route and decodes the response in v when its
+// status is 200.
+func post(t *testing.T, server http.Handler, route, body string, v any) *httptest.ResponseRecorder {
+	t.Helper()
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, httptest.NewRequest(http.MethodPost, route, strings.NewReader(body)))
+	if w.Code == http.StatusOK && v != nil {
+		if err := json.Unmarshal(w.Body.Bytes(), v); err != nil {
+			t.Fatalf("%s: %q: %v", route, w.Body, err)
+		}
+	}
+	return w
+}
+
+// postError sends the body to the route and checks it is answered with the
+// error.
+func postError(t *testing.T, server http.Handler, route, body string, status int, code string) {
+	t.Helper()
+	var response struct{ Error api.Error }
+	w := post(t, server, route, body, nil)
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if w.Code != status || response.Error.Code != code {
+		t.Errorf("%s %.40q: %d %s, want %d %s", route, body, w.Code, response.Error.Code, status, code)
+	}
+}
+
+func withTimeout(board, timeout string) string {
+	return `{"board": ` + board + `, "timeout": "` + timeout + `"}`
+}
+
+const twoLights = `[["light", "", "", "", "", ""], ["", "", "", "", "", "", ""], ["", "", "", "", "", "", "", ""],
+	["", "", "", "", "", "", "", "", ""], ["", "", "", "", "", "", "", "", "", ""], ["", "", "", "", "", "", "", "", "", "", ""],
+	["", "", "", "", "", "", "", "", "", ""], ["", "", "", "", "", "", "", "", ""], ["", "", "", "", "", "", "", ""],
+	["", "", "", "", "", "", ""], ["", "", "", "", "", "light"]]`
+
+func TestSolve(t *testing.T) {
+	server := New(Options{})
+	defer server.Close()
+	input1 := readFile(t, "../inputs/input1.json")
+
+	for _, body := range []string{input1, `{"board": ` + input1 + `}`} {
+		var response api.SolveResponse
+		post(t, server, "/solve", body, &response)
+		if !response.Solved || len(response.Moves) == 0 || response.Checks == 0 {
+			t.Errorf("input1: %+v", response)
+		}
+	}
+	var response api.SolveResponse
+	if w := post(t, server, "/solve", twoLights, &response); w.Code != http.StatusOK || response.Solved || response.Moves == nil {
+		t.Errorf("two lights: %d %+v", w.Code, response)
+	}
+
+	limited := New(Options{MaxChecks: 10})
+	defer limited.Close()
+	postError(t, limited, "/solve", input1, http.StatusUnprocessableEntity, "search_limit")
+}
+
+func TestErrors(t *testing.T) {
+	server := New(Options{MaxBodySize: 1 << 12})
+	defer server.Close()
+	input1 := readFile(t, "../inputs/input1.json")
+
+	for _, route := range []string{"/solve", "/validate", "/hint"} {
+		postError(t, server, route, ``, http.StatusBadRequest, "missing_board")
+		postError(t, server, route, `{"board": [`, http.StatusBadRequest, "invalid_json")
+		postError(t, server, route, `[["light"]]`, http.StatusBadRequest, "invalid_board")
+		postError(t, server, route, strings.Replace(input1, `"cyan"`, `"purple"`, 1), http.StatusBadRequest, "invalid_board")
+		postError(t, server, route, withTimeout(input1, "soon"), http.StatusBadRequest, "invalid_timeout")
+		postError(t, server, route, withTimeout(input1, "-1s"), http.StatusBadRequest, "invalid_timeout")
+		postError(t, server, route, input1+strings.Repeat(" ", 1<<12), http.StatusRequestEntityTooLarge, "body_too_large")
+
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, httptest.NewRequest(http.MethodGet, route, nil))
+		if w.Code != http.StatusMethodNotAllowed || w.Header().Get("Allow") != http.MethodPost {
+			t.Errorf("GET %s: %d, Allow %q", route, w.Code, w.Header().Get("Allow"))
+		}
+	}
+	postError(t, server, "/nowhere", input1, http.StatusNotFound, "not_found")
+}
+
+func TestValidate(t *testing.T) {
+	server := New(Options{})
+	defer server.Close()
+
+	var response api.ValidateResponse
+	post(t, server, "/validate", readFile(t, "../inputs/input1.json"), &response)
+	if !response.Valid || len(response.Problems) != 0 {
+		t.Errorf("input1: %+v", response)
+	}
+	post(t, server, "/validate", twoLights, &response)
+	if response.Valid || len(response.Problems) == 0 || response.Problems[0].Code == "" {
+		t.Errorf("two lights: %+v", response)
+	}
+}
+
+func TestHint(t *testing.T) {
+	server := New(Options{})
+	defer server.Close()
+
+	var response api.HintResponse
+	if w := post(t, server, "/hint", readFile(t, "../inputs/input1.json"), &response); w.Code != http.StatusOK || response.Move.Type1 == "" {
+		t.Errorf("input1: %d %+v", w.Code, response)
+	}
+	postError(t, server, "/hint", twoLights, http.StatusUnprocessableEntity, "no_safe_move")
+}
+
+func TestGenerate(t *testing.T) {
+	server := New(Options{})
+	defer server.Close()
+
+	var first, second api.GenerateResponse
+	post(t, server, "/generate", `{"seed": 42}`, &first)
+	post(t, server, "/generate", `{"seed": 42}`, &second)
+	if first.Seed != 42 || first.Board == nil || !equalJSON(first.Board, second.Board) {
+		t.Errorf("seed 42 gave %+v then %+v", first, second)
+	}
+	var solved api.SolveResponse
+	board, _ := json.Marshal(first.Board)
+	if post(t, server, "/solve", string(board), &solved); !solved.Solved {
+		t.Errorf("generated board not solved: %+v", solved)
+	}
+}
+
+func equalJSON(a, b any) bool {
+	x, _ := json.Marshal(a)
+	y, _ := json.Marshal(b)
+	return bytes.Equal(x, y)
+}
+
+// input_invalid has no solution, its search takes more than ten seconds
+func TestTimeout(t *testing.T) {
+	invalid := readFile(t, "../inputs/input_invalid.json")
+
+	server := New(Options{})
+	defer server.Close()
+	start := time.Now()
+	postError(t, server, "/solve", withTimeout(invalid, "50ms"), http.StatusGatewayTimeout, "timeout")
+
+	capped := New(Options{MaxTimeout: 50 * time.Millisecond})
+	defer capped.Close()
+	postError(t, capped, "/solve", withTimeout(invalid, "1h"), http.StatusGatewayTimeout, "timeout")
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("the timeouts took %s", d)
+	}
+}
+
+// a request waits for a slot within its timeout
+func TestConcurrency(t *testing.T) {
+	invalid := readFile(t, "../inputs/input_invalid.json")
+	server := New(Options{MaxConcurrent: 1})
+	defer server.Close()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		postError(t, server, "/solve", withTimeout(invalid, "1s"), http.StatusGatewayTimeout, "timeout")
+	}()
+	for len(server.slots) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	postError(t, server, "/validate", withTimeout(invalid, "50ms"), http.StatusServiceUnavailable, "busy")
+	<-done
+
+	var response api.ValidateResponse
+	if w := post(t, server, "/validate", withTimeout(invalid, "50ms"), &response); w.Code != http.StatusOK || !response.Valid {
+		t.Errorf("validate once the slot is free: %d %+v", w.Code, response)
+	}
+}
+
+// the editor is served from the binary and loads nothing from elsewhere
+func TestUI(t *testing.T) {
+	page := readFile(t, "ui/index.html")
+	server := New(Options{UI: true})
+	defer server.Close()
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "text/html") || w.Body.String() != page {
+		t.Fatalf("GET /: %d %s, %d bytes, want the %d of ui/index.html", w.Code, w.Header().Get("Content-Type"), w.Body.Len(), len(page))
+	}
+	for _, external := range []string{"src=", "href=", "@import", "url(", "http://", "https://", "//cdn"} {
+		if strings.Contains(page, external) {
+			t.Errorf("the page holds %q", external)
+		}
+	}
+
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/index.js", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("GET /index.js: %d", w.Code)
+	}
+	withoutUI := New(Options{})
+	defer withoutUI.Close()
+	w = httptest.NewRecorder()
+	withoutUI.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("GET / without the UI: %d", w.Code)
+	}
+}
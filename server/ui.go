@@ -0,0 +1,28 @@
+package server
+
+import (
+	"embed"
+	"net/http"
+
+	"sigmars-garden-solver/api"
+)
+
+//go:embed ui/index.html
+var uiFiles embed.FS
+
+// handleUI serves the board editor at /. It only calls the routes of the
+// API, so it needs no other file.
+func (this *Server) handleUI(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		writeError(w, api.NewError(http.StatusNotFound, "not_found", "no route %s", r.URL.Path))
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", http.MethodGet)
+		writeError(w, api.NewError(http.StatusMethodNotAllowed, "method_not_allowed", "use GET"))
+		return
+	}
+	page, _ := uiFiles.ReadFile("ui/index.html")
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(page)
+}
@@ -0,0 +1,73 @@
+package sigmar
+
+import (
+	srcs "sigmars-garden-solver/internal/srcs"
+)
+
+// Game plays a board move after move, for the bots and the programs
+// searching on their own. Unlike the other types of the package, a Game
+// changes as it is played: Clone it to explore a move without losing the
+// current state.
+type Game struct {
+	game *srcs.Game
+}
+
+func NewGame(board Board) *Game {
+	return &Game{srcs.NewGame(board.internal())}
+}
+
+// Board returns the board as played.
+func (this *Game) Board() Board {
+	return boardOf(this.game.Board())
+}
+
+// Moves returns the moves played, in order.
+func (this *Game) Moves() []Move {
+	var moves []Move
+	for _, action := range this.game.Actions() {
+		moves = append(moves, newMove(action))
+	}
+	return moves
+}
+
+// LegalMoves returns the moves Apply accepts, ordered by their positions.
+// Both positions of a move are the same for the gold removed alone.
+func (this *Game) LegalMoves() []Move {
+	var moves []Move
+	for _, action := range this.game.LegalMoves() {
+		moves = append(moves, newMove(action))
+	}
+	return moves
+}
+
+// Apply plays the move, given by its positions in any order, or returns
+// an error wrapping ErrIllegalMove. The types of the move are ignored.
+func (this *Game) Apply(move Move) error {
+	_, err := this.game.Apply(move.X1, move.Y1, move.X2, move.Y2)
+	return err
+}
+
+// Undo takes back the last move, false if none was played.
+func (this *Game) Undo() bool {
+	return this.game.Undo()
+}
+
+func (this *Game) IsWon() bool {
+	return this.game.IsWon()
+}
+
+// IsStuck tells if tiles are left but no move is legal.
+func (this *Game) IsStuck() bool {
+	return this.game.IsStuck()
+}
+
+// Clone returns a game played independently, with the same moves to undo.
+func (this *Game) Clone() *Game {
+	return &Game{this.game.Clone()}
+}
+
+// Hash identifies the tiles left: games with the same tiles left have the
+// same hash, whatever the moves that led to them.
+func (this *Game) Hash() uint64 {
+	return this.game.Hash()
+}
@@ -0,0 +1,80 @@
+package sigmar_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"sigmars-garden-solver/sigmar"
+)
+
+func TestGame(t *testing.T) {
+	board := loadBoard(t, "../inputs/input1.json")
+	solution, err := sigmar.Solve(board, sigmar.Options{Timeout: time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	game := sigmar.NewGame(board)
+	start := game.Hash()
+	for _, move := range solution.Moves() {
+		legal := false
+		for _, m := range game.LegalMoves() {
+			legal = legal || m == move || m == sigmar.Move{X1: move.X2, Y1: move.Y2, X2: move.X1, Y2: move.Y1, Type1: move.Type2, Type2: move.Type1}
+		}
+		if !legal {
+			t.Fatalf("%v is not in the legal moves", move)
+		}
+		if err := game.Apply(move); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !game.IsWon() || game.IsStuck() || !game.Board().IsCleared() {
+		t.Errorf("won %v, stuck %v after the solution", game.IsWon(), game.IsStuck())
+	}
+	if err := sigmar.Verify(board, game.Moves()); err != nil {
+		t.Errorf("the moves played do not verify: %v", err)
+	}
+
+	clone := game.Clone()
+	for clone.Undo() {
+	}
+	if clone.Board() != board || clone.Hash() != start || !game.IsWon() {
+		t.Error("undoing the moves of the clone does not restore the board only")
+	}
+	if err := clone.Apply(sigmar.Move{X1: 5, Y1: 5, X2: 5, Y2: 5}); !errors.Is(err, sigmar.ErrIllegalMove) {
+		t.Errorf("apply of an illegal move: %v, want %v", err, sigmar.ErrIllegalMove)
+	}
+}
+
+// A bot playing the first legal move until it cannot: the gold is left,
+// the other metals being missing.
+func ExampleGame() {
+	board, err := sigmar.Parse([]byte(`[
+		["white", "", "", "", "", ""],
+		["", "", "", "", "", "", ""],
+		["", "", "", "", "", "", "", ""],
+		["", "", "", "", "", "", "", "", ""],
+		["", "", "", "", "", "", "", "", "", ""],
+		["orange", "", "", "", "", "l6", "", "", "", "", "white"],
+		["", "", "", "", "", "", "", "", "", ""],
+		["", "", "", "", "", "", "", "", ""],
+		["", "", "", "", "", "", "", ""],
+		["", "", "", "", "", "", ""],
+		["", "", "", "", "", "orange"]
+	]`))
+	if err != nil {
+		panic(err)
+	}
+	game := sigmar.NewGame(board)
+	for moves := game.LegalMoves(); len(moves) > 0; moves = game.LegalMoves() {
+		fmt.Println(moves[0])
+		game.Apply(moves[0])
+	}
+	fmt.Println(game.IsWon(), game.IsStuck())
+	// Output:
+	// white (0,0) orange (5,0)
+	// white (5,10) orange (10,5)
+	// false true
+}
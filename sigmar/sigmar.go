@@ -0,0 +1,317 @@
+// Package sigmar solves the boards of Sigmar's Garden. It is the stable API
+// of the repository: its types are values, which cannot alter the state of
+// the solver, and the solver itself is internal.
+//
+//	board, err := sigmar.Parse(data)
+//	solution, err := sigmar.Solve(board, sigmar.Options{Timeout: 10 * time.Second})
+package sigmar
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	srcs "sigmars-garden-solver/internal/srcs"
+	"sigmars-garden-solver/render"
+)
+
+// TileType is the type of a tile, as written in the JSON boards.
+type TileType string
+
+const (
+	TileType_EMPTY  TileType = ""
+	TileType_WHITE  TileType = "white" // salt
+	TileType_CYAN   TileType = "cyan"  // air
+	TileType_ORANGE TileType = "orange"
+	TileType_BLUE   TileType = "blue"
+	TileType_GREEN  TileType = "green"
+	TileType_LIGHT  TileType = "light"
+	TileType_DARK   TileType = "dark"
+	TileType_KEY    TileType = "key" // quicksilver
+	TileType_L1     TileType = "l1"  // metals, removed in their order
+	TileType_L2     TileType = "l2"
+	TileType_L3     TileType = "l3"
+	TileType_L4     TileType = "l4"
+	TileType_L5     TileType = "l5"
+	TileType_L6     TileType = "l6" // gold, removed alone
+)
+
+var (
+	ErrInvalidBoard = srcs.ErrInvalidBoard
+	ErrIllegalMove  = srcs.ErrIllegalMove
+	ErrSearchLimit  = srcs.ErrSearchLimit // the search stopped at Options.MaxChecks
+	ErrNotSolved    = errors.New("tiles are left after the last move")
+)
+
+const nbTiles = 91
+
+// Board is a board of 11 rows of 6 to 11 tiles, the middle row being the
+// longest. The zero Board is the empty board.
+type Board struct {
+	tiles [nbTiles]TileType
+}
+
+// Parse reads a board written as a JSON array of its rows.
+func Parse(data []byte) (Board, error) {
+	var tiles [][]TileType
+	if err := json.Unmarshal(data, &tiles); err != nil {
+		return Board{}, fmt.Errorf("%w: %v", ErrInvalidBoard, err)
+	}
+	return NewBoard(tiles)
+}
+
+// NewBoard returns the board of the rows of tiles.
+func NewBoard(tiles [][]TileType) (Board, error) {
+	internal := make([][]srcs.TileType, len(tiles))
+	for x, line := range tiles {
+		for _, tile := range line {
+			internal[x] = append(internal[x], srcs.TileType(tile))
+		}
+	}
+	if _, err := srcs.ParseBoard(internal); err != nil {
+		return Board{}, err
+	}
+	var board Board
+	for x, line := range tiles {
+		for y, tile := range line {
+			board.tiles[srcs.FromXYPos(x, y)] = tile
+		}
+	}
+	return board, nil
+}
+
+// Tile returns the tile at row x and column y, TileType_EMPTY out of the
+// board.
+func (this Board) Tile(x, y int) TileType {
+	if !srcs.IsPossitionValid(x, y) {
+		return TileType_EMPTY
+	}
+	return this.tiles[srcs.FromXYPos(x, y)]
+}
+
+// Tiles returns a copy of the rows of the board.
+func (this Board) Tiles() [][]TileType {
+	tiles := make([][]TileType, 0, 11)
+	for i, tile := range this.tiles {
+		x, _ := srcs.ToXYPos(i)
+		if x == len(tiles) {
+			tiles = append(tiles, nil)
+		}
+		tiles[x] = append(tiles[x], tile)
+	}
+	return tiles
+}
+
+// IsCleared tells if the board has no tile left.
+func (this Board) IsCleared() bool {
+	return this.tiles == [nbTiles]TileType{}
+}
+
+func (this Board) MarshalJSON() ([]byte, error) {
+	return json.Marshal(this.Tiles())
+}
+
+func (this *Board) UnmarshalJSON(data []byte) error {
+	board, err := Parse(data)
+	if err != nil {
+		return err
+	}
+	*this = board
+	return nil
+}
+
+func (this Board) internal() srcs.Board {
+	tiles := make([][]srcs.TileType, 0, 11)
+	for _, line := range this.Tiles() {
+		internal := make([]srcs.TileType, len(line))
+		for y, tile := range line {
+			internal[y] = srcs.TileType(tile)
+		}
+		tiles = append(tiles, internal)
+	}
+	return srcs.NewBoard(tiles)
+}
+
+func boardOf(internal srcs.Board) Board {
+	var board Board
+	for i, tile := range internal.Board {
+		board.tiles[i] = TileType(tile.Type)
+	}
+	return board
+}
+
+// Move removes two tiles, or the last metal alone when both positions are
+// the same.
+type Move struct {
+	X1    int      `json:"x1"`
+	Y1    int      `json:"y1"`
+	X2    int      `json:"x2"`
+	Y2    int      `json:"y2"`
+	Type1 TileType `json:"type1"`
+	Type2 TileType `json:"type2"`
+}
+
+func (this Move) String() string {
+	if this.X1 == this.X2 && this.Y1 == this.Y2 {
+		return fmt.Sprintf("%s (%d,%d)", this.Type1, this.X1, this.Y1)
+	}
+	return fmt.Sprintf("%s (%d,%d) %s (%d,%d)", this.Type1, this.X1, this.Y1, this.Type2, this.X2, this.Y2)
+}
+
+func newMove(action srcs.Action) Move {
+	return Move{action.X1, action.Y1, action.X2, action.Y2, TileType(action.Type1), TileType(action.Type2)}
+}
+
+func (this Move) action() srcs.Action {
+	return srcs.Action{X1: this.X1, Y1: this.Y1, X2: this.X2, Y2: this.Y2, Type1: srcs.TileType(this.Type1), Type2: srcs.TileType(this.Type2)}
+}
+
+// Solution is the result of Solve.
+type Solution struct {
+	solved   bool
+	moves    []Move
+	checks   int64
+	duration time.Duration
+}
+
+// Solved tells if the board can be cleared, by Moves.
+func (this Solution) Solved() bool {
+	return this.solved
+}
+
+// Moves returns a copy of the moves clearing the board, none if it cannot
+// be cleared.
+func (this Solution) Moves() []Move {
+	return append([]Move{}, this.moves...)
+}
+
+// Checks returns the number of moves tried by the search.
+func (this Solution) Checks() int64 {
+	return this.checks
+}
+
+func (this Solution) Duration() time.Duration {
+	return this.duration
+}
+
+func (this Solution) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Solved   bool   `json:"solved"`
+		Moves    []Move `json:"moves"`
+		Checks   int64  `json:"checks"`
+		Duration string `json:"duration"`
+	}{this.solved, this.Moves(), this.checks, this.duration.String()})
+}
+
+// Options bound a search. The zero Options search without limit.
+type Options struct {
+	Context   context.Context // nil for no cancellation
+	Timeout   time.Duration   // 0 for no limit
+	MaxChecks int64           // 0 for no limit
+}
+
+func (this Options) context() (context.Context, context.CancelFunc) {
+	ctx := this.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if this.Timeout > 0 {
+		return context.WithTimeout(ctx, this.Timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// Solve searches the moves clearing the board. A board without solution is
+// not an error: the Solution is not Solved. The error is ErrSearchLimit or
+// the error of the context when the search stopped before knowing.
+func Solve(board Board, opts Options) (Solution, error) {
+	if board.IsCleared() {
+		return Solution{solved: true}, nil
+	}
+	ctx, cancel := opts.context()
+	defer cancel()
+
+	internal := board.internal()
+	actions, n, dur, err := internal.SolveWithOptions(srcs.SolveOptions{Context: ctx, MaxChecks: opts.MaxChecks, Quiet: true})
+	solution := Solution{solved: len(actions) > 0, checks: n, duration: dur}
+	for _, action := range actions {
+		solution.moves = append(solution.moves, newMove(action))
+	}
+	return solution, err
+}
+
+// Verify plays the moves on the board, returning an error wrapping
+// ErrIllegalMove at the first illegal one, or ErrNotSolved if tiles are
+// left after the last one.
+func Verify(board Board, moves []Move) error {
+	internal := board.internal()
+	for i, move := range moves {
+		if _, err := internal.Move(move.X1, move.Y1, move.X2, move.Y2); err != nil {
+			return fmt.Errorf("move %d: %w", i+1, err)
+		}
+	}
+	for _, tile := range internal.Board {
+		if tile.Type != srcs.TileType_EMPTY {
+			return ErrNotSolved
+		}
+	}
+	return nil
+}
+
+// Hint returns a move after which the board can still be cleared, false
+// if there is none. The error is ErrSearchLimit or the error of the context
+// when no such move was found but some moves could not be told apart.
+func Hint(board Board, opts Options) (Move, bool, error) {
+	ctx, cancel := opts.context()
+	defer cancel()
+
+	oracle := srcs.NewOracle(opts.MaxChecks)
+	oracle.Context = ctx
+	undecided := false
+	for _, move := range oracle.Moves(board.internal()) {
+		if move.Outcome == srcs.Outcome_WIN {
+			return newMove(move.Action), true, nil
+		}
+		undecided = undecided || move.Outcome == srcs.Outcome_UNKNOWN
+	}
+	if !undecided {
+		return Move{}, false, nil
+	}
+	if ctx.Err() != nil {
+		return Move{}, false, ctx.Err()
+	}
+	return Move{}, false, ErrSearchLimit
+}
+
+type Format string
+
+const (
+	Format_SVG Format = "svg"
+	Format_PNG Format = "png"
+)
+
+type RenderOptions struct {
+	Format  Format  // Format_SVG if empty
+	HexSize float64 // radius of a tile in pixels, 24 if 0
+	Moves   []Move  // drawn as numbered arrows, in order
+}
+
+// Render draws the board, and the moves of the options, as SVG or PNG.
+func Render(w io.Writer, board Board, opts RenderOptions) error {
+	internal := board.internal()
+	renderOpts := render.Options{HexSize: opts.HexSize}
+	for _, move := range opts.Moves {
+		renderOpts.Actions = append(renderOpts.Actions, move.action())
+	}
+	switch opts.Format {
+	case Format_SVG, "":
+		return render.SVG(w, &internal, renderOpts)
+	case Format_PNG:
+		return render.PNG(w, &internal, renderOpts)
+	default:
+		return fmt.Errorf("unknown format %q", opts.Format)
+	}
+}
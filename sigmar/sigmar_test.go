@@ -0,0 +1,165 @@
+package sigmar_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/png"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"sigmars-garden-solver/sigmar"
+)
+
+func loadBoard(t testing.TB, path string) sigmar.Board {
+	t.Helper()
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	board, err := sigmar.Parse(data)
+	if err != nil {
+		t.Fatalf("%s: %v", path, err)
+	}
+	return board
+}
+
+func TestSolveVerify(t *testing.T) {
+	board := loadBoard(t, "../inputs/input1.json")
+	solution, err := sigmar.Solve(board, sigmar.Options{Timeout: time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !solution.Solved() || len(solution.Moves()) == 0 {
+		t.Fatalf("input1 is not solved: %+v", solution)
+	}
+	if err := sigmar.Verify(board, solution.Moves()); err != nil {
+		t.Fatal(err)
+	}
+
+	moves := solution.Moves()
+	moves[0], moves[1] = moves[1], moves[0]
+	if err := sigmar.Verify(board, moves); err == nil {
+		t.Error("the solution with its first moves swapped still verifies")
+	}
+	if err := sigmar.Verify(board, solution.Moves()); err != nil {
+		t.Errorf("changing the moves changed the solution: %v", err)
+	}
+	if err := sigmar.Verify(board, solution.Moves()[:1]); !errors.Is(err, sigmar.ErrNotSolved) {
+		t.Errorf("verify of the first move: %v, want %v", err, sigmar.ErrNotSolved)
+	}
+	if err := sigmar.Verify(board, []sigmar.Move{{X1: 0, Y1: 0, X2: 0, Y2: 0}}); !errors.Is(err, sigmar.ErrIllegalMove) {
+		t.Errorf("verify of an illegal move: %v, want %v", err, sigmar.ErrIllegalMove)
+	}
+}
+
+func TestBoardIsValue(t *testing.T) {
+	board := loadBoard(t, "../inputs/input1.json")
+	tiles := board.Tiles()
+	if len(tiles) != 11 || len(tiles[0]) != 6 || len(tiles[5]) != 11 {
+		t.Fatalf("rows of %d, %d and %d tiles", len(tiles), len(tiles[0]), len(tiles[5]))
+	}
+	if board.Tile(5, 5) != tiles[5][5] || board.Tile(0, 10) != sigmar.TileType_EMPTY {
+		t.Errorf("Tile and Tiles differ")
+	}
+
+	tile := tiles[5][5]
+	tiles[5][5] = sigmar.TileType_EMPTY
+	if board.Tile(5, 5) != tile {
+		t.Error("changing the tiles changed the board")
+	}
+
+	data, err := json.Marshal(board)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded sigmar.Board
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded != board {
+		t.Error("the board changed in JSON")
+	}
+	if (sigmar.Board{}).IsCleared() != true || board.IsCleared() {
+		t.Error("IsCleared is wrong")
+	}
+}
+
+func TestErrors(t *testing.T) {
+	for _, data := range []string{`[`, `[["white"]]`, `{}`} {
+		if _, err := sigmar.Parse([]byte(data)); !errors.Is(err, sigmar.ErrInvalidBoard) {
+			t.Errorf("Parse(%s): %v, want %v", data, err, sigmar.ErrInvalidBoard)
+		}
+	}
+
+	board := loadBoard(t, "../inputs/input3.json")
+	solution, err := sigmar.Solve(board, sigmar.Options{MaxChecks: 10})
+	if !errors.Is(err, sigmar.ErrSearchLimit) || solution.Solved() {
+		t.Errorf("Solve with 10 checks: %v, %v", solution.Solved(), err)
+	}
+	solution, err = sigmar.Solve(sigmar.Board{}, sigmar.Options{})
+	if err != nil || !solution.Solved() || len(solution.Moves()) != 0 {
+		t.Errorf("Solve of the empty board: %+v, %v", solution, err)
+	}
+}
+
+func TestHint(t *testing.T) {
+	board := loadBoard(t, "../inputs/input1.json")
+	move, found, err := sigmar.Hint(board, sigmar.Options{Timeout: time.Minute})
+	if err != nil || !found {
+		t.Fatalf("no hint: %v", err)
+	}
+	if move.Type1 == sigmar.TileType_EMPTY || board.Tile(move.X1, move.Y1) != move.Type1 || board.Tile(move.X2, move.Y2) != move.Type2 {
+		t.Errorf("hint %v is not on the board", move)
+	}
+	if _, found, err := sigmar.Hint(sigmar.Board{}, sigmar.Options{}); found || err != nil {
+		t.Errorf("hint on the empty board: %v, %v", found, err)
+	}
+}
+
+func TestRender(t *testing.T) {
+	board := loadBoard(t, "../inputs/input1.json")
+	solution, err := sigmar.Solve(board, sigmar.Options{Timeout: time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var svg bytes.Buffer
+	if err := sigmar.Render(&svg, board, sigmar.RenderOptions{Moves: solution.Moves()[:3]}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(svg.String(), "<svg") {
+		t.Errorf("not an SVG: %.40s", svg.String())
+	}
+
+	var img bytes.Buffer
+	if err := sigmar.Render(&img, board, sigmar.RenderOptions{Format: sigmar.Format_PNG, HexSize: 10}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := png.Decode(&img); err != nil {
+		t.Error(err)
+	}
+	if err := sigmar.Render(&img, board, sigmar.RenderOptions{Format: "gif"}); err == nil {
+		t.Error("rendered a gif")
+	}
+}
+
+func ExampleSolve() {
+	data, err := ioutil.ReadFile("../inputs/input1.json")
+	if err != nil {
+		panic(err)
+	}
+	board, err := sigmar.Parse(data)
+	if err != nil {
+		panic(err)
+	}
+	solution, err := sigmar.Solve(board, sigmar.Options{Timeout: 10 * time.Second})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(solution.Solved(), len(solution.Moves()), sigmar.Verify(board, solution.Moves()))
+	// Output: true 28 <nil>
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	. "sigmars-garden-solver/internal/srcs"
+	"sigmars-garden-solver/solvers"
+	"time"
+)
+
+type solveOutput struct {
+	Solved   bool     `json:"solved"`
+	Moves    []Action `json:"moves"`
+	Checks   int64    `json:"checks"`
+	Duration string   `json:"duration"`
+	Cache    string   `json:"cache,omitempty"` // hit or miss, with -cache
+
+	// the other backend agrees, or is undecided, with -crosscheck
+	CrossCheck string `json:"crosscheck,omitempty"`
+}
+
+func solveMain(args []string) {
+	flags := newFlagSet("solve")
+	cacheDir := flags.String("cache", "", "directory of the solution cache, none if empty")
+	crossCheck := flags.Bool("crosscheck", false, "solve the board again with the other backend, within the same limits, and exit with 4 if they disagree")
+	common := addBoardFlags(flags).addSearchFlags(flags).addSolverFlags(flags)
+	flags.Parse(args)
+	common.check()
+
+	board := loadBoard(inputArg(flags))
+	tiles := board.Tiles()
+	opts, cancel := common.solveOptions()
+	defer cancel()
+
+	var actions []Action
+	var n int64
+	var dur time.Duration
+	var err error
+	var cache *SolutionCache
+	hit := false
+	if *cacheDir == "" {
+		actions, n, dur, err = board.SolveWithOptions(opts)
+	} else {
+		if cache, err = OpenSolutionCache(*cacheDir); err != nil {
+			fail(ExitCode_INVALID, "cache: %v", err)
+		}
+		actions, n, dur, hit, err = cache.Solve(&board, opts)
+	}
+	if err != nil && len(actions) == 0 {
+		fail(searchExitCode(err), "no solution found after %d checks: %v", n, err)
+	} else if err != nil {
+		// the solution was found but not stored
+		fmt.Fprintln(os.Stderr, "cache:", err)
+	}
+
+	output := solveOutput{Solved: len(actions) > 0, Moves: actions, Checks: n, Duration: dur.String()}
+	if output.Moves == nil {
+		output.Moves = []Action{}
+	}
+	if *crossCheck {
+		output.CrossCheck = crossCheckBackends(tiles, opts.Backend, actions, common)
+	}
+	if cache != nil && hit {
+		output.Cache = "hit"
+	} else if cache != nil {
+		output.Cache = "miss"
+	}
+	switch {
+	case common.json():
+		printJSON(output)
+	case *common.quiet:
+		fmt.Print(SolutionToString(actions))
+	default:
+		printSolution(actions, n, dur)
+		if cache != nil {
+			fmt.Printf("cache: %s, %v\n", output.Cache, cache.Stats())
+		}
+		if output.CrossCheck != "" {
+			fmt.Println("crosscheck:", output.CrossCheck)
+		}
+	}
+	if !output.Solved {
+		os.Exit(int(ExitCode_UNSOLVABLE))
+	}
+}
+
+// crossCheckBackends solves the tiles with the backend other than backend
+// and exits with ExitCode_MISMATCH if the verdicts differ or a solution
+// does not verify. It returns how the other backend ended.
+func crossCheckBackends(tiles [][]TileType, backend Backend, actions []Action, common *boardFlags) string {
+	other := Backend_SAT
+	if backend == Backend_SAT {
+		other = Backend_DFS
+	}
+	opts, cancel := common.solveOptions()
+	defer cancel()
+	opts.Backend = other
+	board := NewBoard(tiles)
+	otherActions, n, _, err := board.SolveWithOptions(opts)
+
+	for _, run := range []struct {
+		backend Backend
+		actions []Action
+	}{{backend, actions}, {other, otherActions}} {
+		if len(run.actions) == 0 {
+			continue
+		}
+		if err := solvers.Verify(tiles, run.actions); err != nil {
+			fail(ExitCode_MISMATCH, "crosscheck: the solution of %s does not verify: %v", run.backend, err)
+		}
+	}
+	if err != nil {
+		return fmt.Sprintf("%s undecided after %d checks: %v", other, n, err)
+	}
+	if (len(otherActions) > 0) != (len(actions) > 0) {
+		fail(ExitCode_MISMATCH, "crosscheck: %s found %d moves, %s %d", backend, len(actions), other, len(otherActions))
+	}
+	return fmt.Sprintf("%s agrees after %d checks", other, n)
+}
+
+func printSolution(actions []Action, n int64, dur time.Duration) {
+	fmt.Println("total checks:", n)
+	fmt.Println("total duration:", dur)
+	if len(actions) == 0 {
+		fmt.Println("no solution")
+	}
+	fmt.Println(SolutionToString(actions))
+}
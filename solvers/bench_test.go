@@ -0,0 +1,85 @@
+package solvers
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	. "sigmars-garden-solver/internal/srcs"
+)
+
+// corpus are the boards timed by the benchmarks: the solvable boards of
+// inputs/ and the generated boards of testdata/corpus/, picked for the
+// different search sizes they need.
+var corpus = []string{
+	"../inputs/input1.json",
+	"../inputs/input2.json",
+	"../inputs/input3.json",
+	"../inputs/input4.json",
+	"../testdata/corpus/*.json",
+}
+
+func loadCorpus(b *testing.B) map[string][][]TileType {
+	boards := map[string][][]TileType{}
+	for _, pattern := range corpus {
+		paths, err := filepath.Glob(pattern)
+		if err != nil || len(paths) == 0 {
+			b.Fatalf("%s: no board", pattern)
+		}
+		for _, path := range paths {
+			byteValue, err := ioutil.ReadFile(path)
+			if err != nil {
+				b.Fatal(err)
+			}
+			var tiles [][]TileType
+			if err := json.Unmarshal(byteValue, &tiles); err != nil {
+				b.Fatalf("%s: %v", path, err)
+			}
+			boards[strings.TrimSuffix(filepath.Base(path), ".json")] = tiles
+		}
+	}
+	return boards
+}
+
+// BenchmarkSolvers times every solver on every board of the corpus,
+// reporting the checks of a solve and the checks per second. v0 is skipped
+// on the boards it does not solve within a minute.
+//
+//	go test -run '^$' -bench . -benchtime 3x ./solvers
+func BenchmarkSolvers(b *testing.B) {
+	boards := loadCorpus(b)
+	var names []string
+	for name := range boards {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, solver := range All {
+		for _, name := range names {
+			solver, name, tiles := solver, name, boards[name]
+			b.Run(solver.Name+"/"+name, func(b *testing.B) {
+				b.ReportAllocs()
+				var checks int64
+				var dur time.Duration
+				for i := 0; i < b.N; i++ {
+					ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+					result, err := solver.Solve(ctx, tiles, 0)
+					cancel()
+					if err != nil {
+						b.Skipf("%s: %v", name, err)
+					}
+					checks += result.Checks
+					dur += result.Duration
+				}
+				b.ReportMetric(float64(checks)/float64(b.N), "checks/op")
+				if dur > 0 {
+					b.ReportMetric(float64(checks)/dur.Seconds(), "checks/s")
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package solvers
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	. "sigmars-garden-solver/internal/srcs"
+)
+
+type Verdict string
+
+const (
+	Verdict_SOLVED     Verdict = "solved"
+	Verdict_UNSOLVABLE Verdict = "unsolvable"
+	Verdict_WRONG      Verdict = "wrong"   // the solution does not verify
+	Verdict_TIMEOUT    Verdict = "timeout" // or the search limit, no verdict
+	Verdict_INVALID    Verdict = "invalid"
+)
+
+// Run is the outcome of a solver on a board.
+type Run struct {
+	Solver  string  `json:"solver"`
+	Verdict Verdict `json:"verdict"`
+	Error   string  `json:"error,omitempty"`
+	Checks  int64   `json:"checks"`
+	Moves   int     `json:"moves"`
+}
+
+type Limits struct {
+	Timeout   time.Duration // time allowed to each solver, 0 for no limit
+	MaxChecks int64         // checks allowed to each solver, 0 for no limit
+}
+
+// RunAll runs each solver on the tiles within the limits.
+func RunAll(list []Solver, tiles [][]TileType, limits Limits) []Run {
+	runs := make([]Run, len(list))
+	for i, solver := range list {
+		runs[i] = RunOne(solver, tiles, limits)
+	}
+	return runs
+}
+
+// RunOne runs the solver on the tiles within the limits, and verifies the
+// solution found with Verify.
+func RunOne(solver Solver, tiles [][]TileType, limits Limits) Run {
+	ctx, cancel := context.WithCancel(context.Background())
+	if limits.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), limits.Timeout)
+	}
+	result, err := solver.Solve(ctx, tiles, limits.MaxChecks)
+	cancel()
+
+	run := Run{Solver: solver.Name, Checks: result.Checks, Moves: len(result.Actions)}
+	switch {
+	case errors.Is(err, ErrInvalidBoard):
+		run.Verdict, run.Error = Verdict_INVALID, err.Error()
+	case err != nil:
+		run.Verdict, run.Error = Verdict_TIMEOUT, err.Error()
+	case len(result.Actions) == 0:
+		run.Verdict = Verdict_UNSOLVABLE
+	default:
+		run.Verdict = Verdict_SOLVED
+		if err := Verify(tiles, result.Actions); err != nil {
+			run.Verdict, run.Error = Verdict_WRONG, err.Error()
+		}
+	}
+	return run
+}
+
+// Disagree tells if the runs show a bug: a wrong solution, or a board solved
+// by a solver and found without solution by another. Timeouts prove
+// nothing.
+func Disagree(runs []Run) bool {
+	verdicts := map[Verdict]bool{}
+	for _, run := range runs {
+		verdicts[run.Verdict] = true
+	}
+	return verdicts[Verdict_WRONG] || verdicts[Verdict_SOLVED] && verdicts[Verdict_UNSOLVABLE]
+}
+
+// Discrepancy is a board the solvers disagree on, with the smallest board
+// found where they disagree the same way.
+type Discrepancy struct {
+	Tiles         [][]TileType `json:"tiles"`
+	Runs          []Run        `json:"runs"`
+	Minimized     [][]TileType `json:"minimized"`
+	MinimizedRuns []Run        `json:"minimized_runs"`
+}
+
+// Diff runs the solvers on the tiles and, if they disagree, minimizes the
+// board while each solver keeps its verdict. Each step of the minimization
+// runs the solvers again in their order, until one changes its verdict:
+// the fastest solvers should come first. A board taking more than 10 times
+// the checks of the original one, and at least 100000, is not kept.
+func Diff(list []Solver, tiles [][]TileType, limits Limits) (Discrepancy, bool) {
+	d := Discrepancy{Tiles: tiles, Runs: RunAll(list, tiles, limits)}
+	if !Disagree(d.Runs) {
+		return d, false
+	}
+
+	minimizeLimits := limits
+	budget := int64(100000)
+	for _, run := range d.Runs {
+		if 10*run.Checks > budget {
+			budget = 10 * run.Checks
+		}
+	}
+	if limits.MaxChecks == 0 || budget < limits.MaxChecks {
+		minimizeLimits.MaxChecks = budget
+	}
+	d.Minimized = Minimize(tiles, KeepsVerdicts(list, d.Runs, minimizeLimits))
+	d.MinimizedRuns = RunAll(list, d.Minimized, limits)
+	return d, true
+}
+
+// Scramble swaps tiles of the board at random, which may leave it without
+// solution, the same seed giving the same swaps.
+func Scramble(tiles [][]TileType, seed int64, swaps int) [][]TileType {
+	rng := rand.New(rand.NewSource(seed))
+	scrambled := make([][]TileType, len(tiles))
+	for x, line := range tiles {
+		scrambled[x] = append([]TileType{}, line...)
+	}
+	for i := 0; i < swaps; i++ {
+		x1, y1 := ToXYPos(rng.Intn(91))
+		x2, y2 := ToXYPos(rng.Intn(91))
+		scrambled[x1][y1], scrambled[x2][y2] = scrambled[x2][y2], scrambled[x1][y1]
+	}
+	return scrambled
+}
@@ -0,0 +1,80 @@
+package solvers
+
+import (
+	"testing"
+	"time"
+
+	. "sigmars-garden-solver/internal/srcs"
+)
+
+func diffBoard(t *testing.T, seed int64, swaps int) [][]TileType {
+	t.Helper()
+	board, err := Generate(seed, GenerateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Scramble(board.Tiles(), seed, swaps)
+}
+
+// the configurations of the current solver agree, scrambled boards
+// included
+func TestDiffSrcs(t *testing.T) {
+	var list []Solver
+	for _, name := range []string{"srcs-memo", "srcs-none", "srcs"} {
+		solver, err := Get(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		list = append(list, solver)
+	}
+	for seed := int64(1); seed <= 6; seed++ {
+		d, found := Diff(list, diffBoard(t, seed, 4), Limits{MaxChecks: 1000000})
+		if found {
+			t.Errorf("seed %d: %v, minimized to %v: %v", seed, d.Runs, d.Minimized, d.MinimizedRuns)
+		}
+	}
+}
+
+// v0 has the neighbors of the middle row wrong: its solutions of some
+// boards play locked tiles, found again on the minimized board
+func TestDiffV0(t *testing.T) {
+	srcs, _ := Get("srcs")
+	v0, _ := Get("v0")
+	d, found := Diff([]Solver{srcs, v0}, diffBoard(t, 1, 2), Limits{Timeout: 10 * time.Second})
+	if !found {
+		t.Fatalf("no discrepancy: %v", d.Runs)
+	}
+	if d.Runs[1].Verdict != Verdict_WRONG || d.MinimizedRuns[0].Verdict != Verdict_SOLVED || d.MinimizedRuns[1].Verdict != Verdict_WRONG {
+		t.Fatalf("runs %v, minimized %v", d.Runs, d.MinimizedRuns)
+	}
+	n := 0
+	for _, line := range d.Minimized {
+		for _, tile := range line {
+			if tile != TileType_EMPTY {
+				n++
+			}
+		}
+	}
+	if n > 10 {
+		t.Errorf("minimized to %d tiles", n)
+	}
+	if err := Verify(d.Minimized, nil); err == nil {
+		t.Errorf("minimized to an empty board")
+	}
+}
+
+// the SAT backend agrees with the depth first search, and decides boards
+// the search does not within its checks
+func TestDiffSAT(t *testing.T) {
+	memo, _ := Get("srcs-memo")
+	sat, _ := Get("srcs-sat")
+	for seed := int64(1); seed <= 6; seed++ {
+		d, found := Diff([]Solver{memo, sat}, diffBoard(t, seed, 4), Limits{MaxChecks: 1000000})
+		if found {
+			t.Errorf("seed %d: %v, minimized to %v: %v", seed, d.Runs, d.Minimized, d.MinimizedRuns)
+		}
+		if d.Runs[1].Verdict == Verdict_TIMEOUT {
+			t.Errorf("seed %d: %v", seed, d.Runs)
+		}
+	}
+}
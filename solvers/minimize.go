@@ -0,0 +1,50 @@
+package solvers
+
+import (
+	"fmt"
+
+	. "sigmars-garden-solver/internal/srcs"
+)
+
+// The predicates of Minimize, on the runs of solvers.
+
+// KeepsVerdicts tells if the solvers, run in their order, give the verdicts
+// of runs, stopping at the first one that does not.
+func KeepsVerdicts(list []Solver, runs []Run, limits Limits) func(tiles [][]TileType) bool {
+	return func(tiles [][]TileType) bool {
+		for i, solver := range list {
+			if RunOne(solver, tiles, limits).Verdict != runs[i].Verdict {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// ExceedsChecks tells if the solver does not end its search, with or
+// without solution, within maxChecks and the timeout of limits.
+func ExceedsChecks(solver Solver, maxChecks int64, limits Limits) func(tiles [][]TileType) bool {
+	limits.MaxChecks = maxChecks
+	return func(tiles [][]TileType) bool {
+		return RunOne(solver, tiles, limits).Verdict == Verdict_TIMEOUT
+	}
+}
+
+// Panic runs the solver and returns its panic, "" if it did not panic. The
+// panics of v0 are how it ends its search, they are not reported.
+func Panic(solver Solver, tiles [][]TileType, limits Limits) (message string) {
+	defer func() {
+		if r := recover(); r != nil {
+			message = fmt.Sprint(r)
+		}
+	}()
+	RunOne(solver, tiles, limits)
+	return ""
+}
+
+// PanicsWith tells if the solver panics with that message.
+func PanicsWith(solver Solver, message string, limits Limits) func(tiles [][]TileType) bool {
+	return func(tiles [][]TileType) bool {
+		return Panic(solver, tiles, limits) == message
+	}
+}
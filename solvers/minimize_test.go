@@ -0,0 +1,74 @@
+package solvers
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	. "sigmars-garden-solver/internal/srcs"
+)
+
+func countTiles(tiles [][]TileType) map[TileType]int {
+	count := map[TileType]int{}
+	for _, line := range tiles {
+		for _, tile := range line {
+			if tile != TileType_EMPTY {
+				count[tile]++
+			}
+		}
+	}
+	return count
+}
+
+func TestMinimizePanic(t *testing.T) {
+	// panics on light, as a solver with a bug would
+	buggy := Solver{Name: "buggy", Solve: func(ctx context.Context, tiles [][]TileType, maxChecks int64) (Result, error) {
+		if countTiles(tiles)[TileType_LIGHT] > 0 {
+			panic("light")
+		}
+		return Result{}, nil
+	}}
+	board, err := Generate(1, GenerateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	message := Panic(buggy, board.Tiles(), Limits{})
+	if message != "light" {
+		t.Fatalf("Panic() = %q", message)
+	}
+	minimized := Minimize(board.Tiles(), PanicsWith(buggy, message, Limits{}))
+	if count := countTiles(minimized); len(count) != 2 || count[TileType_LIGHT] != 1 || count[TileType_DARK] != 1 {
+		t.Errorf("minimized to %v", count)
+	}
+}
+
+func TestMinimizeChecks(t *testing.T) {
+	byteValue, err := ioutil.ReadFile("../testdata/corpus/generated-118.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tiles [][]TileType
+	if err := json.Unmarshal(byteValue, &tiles); err != nil {
+		t.Fatal(err)
+	}
+	solver, _ := Get("srcs")
+	slow := ExceedsChecks(solver, 10000, Limits{})
+	if !slow(tiles) {
+		t.Fatal("the board is solved within 10000 checks")
+	}
+	minimized := Minimize(tiles, slow)
+	if !slow(minimized) {
+		t.Errorf("the minimized board is solved within 10000 checks")
+	}
+	before, after := 0, 0
+	for _, n := range countTiles(tiles) {
+		before += n
+	}
+	for _, n := range countTiles(minimized) {
+		after += n
+	}
+	if after >= before {
+		t.Errorf("minimized from %d to %d tiles", before, after)
+	}
+}
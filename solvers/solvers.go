@@ -0,0 +1,177 @@
+// Package solvers gives the solver generations of the repository, and the
+// configurations of the current one, a common interface so they can be
+// compared on the same boards.
+package solvers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	. "sigmars-garden-solver/internal/srcs"
+	"sigmars-garden-solver/v0"
+)
+
+type Result struct {
+	Actions  []Action // empty if the board has no solution
+	Checks   int64
+	Duration time.Duration
+}
+
+type Solver struct {
+	Name        string
+	Description string
+
+	// Solve searches a solution of the tiles until ctx is done or maxChecks
+	// are made, 0 for no limit. The error is ErrSearchLimit or the error of
+	// the context when the search is stopped.
+	Solve func(ctx context.Context, tiles [][]TileType, maxChecks int64) (Result, error)
+
+	// Unbounded solvers return when ctx is done but their search keeps
+	// running in the background until it ends.
+	Unbounded bool
+}
+
+// All are the solvers known, the first one being the default solver.
+var All = []Solver{
+	srcsSolver("srcs", "current solver", SolveOptions{}),
+	srcsSolver("srcs-none", "current solver, pairs tried in the order of the board", SolveOptions{Heuristic: Heuristic_NONE}),
+	srcsSolver("srcs-memo", "current solver, remembering the states without solution", SolveOptions{Memo: true}),
+	srcsSolver("srcs-sat", "current rules solved as a SAT formula, checks are its conflicts", SolveOptions{Backend: Backend_SAT}),
+	{Name: "v0", Description: "first solver, unbounded: it keeps running after a timeout", Solve: solveV0, Unbounded: true},
+}
+
+// Get returns the solver of that name.
+func Get(name string) (Solver, error) {
+	var names []string
+	for _, solver := range All {
+		if solver.Name == name {
+			return solver, nil
+		}
+		names = append(names, solver.Name)
+	}
+	return Solver{}, fmt.Errorf("unknown solver %q, one of %s", name, strings.Join(names, ", "))
+}
+
+// Bounded returns an error if the solver cannot keep to the timeout of the
+// limits: an unbounded solver would leave a search running after each one.
+func (this Solver) Bounded(limits Limits) error {
+	if this.Unbounded && limits.Timeout > 0 {
+		return fmt.Errorf("%s cannot be stopped, its searches would keep running after each timeout", this.Name)
+	}
+	return nil
+}
+
+func srcsSolver(name, description string, opts SolveOptions) Solver {
+	return Solver{
+		Name:        name,
+		Description: description,
+		Solve: func(ctx context.Context, tiles [][]TileType, maxChecks int64) (Result, error) {
+			board, err := ParseBoard(tiles)
+			if err != nil {
+				return Result{}, err
+			}
+			opts := opts
+			opts.Context, opts.MaxChecks, opts.Quiet = ctx, maxChecks, true
+			actions, n, dur, err := board.SolveWithOptions(opts)
+			return Result{Actions: actions, Checks: n, Duration: dur}, err
+		},
+	}
+}
+
+// solveV0 runs the v0 solver. It cannot be stopped nor bounded: the search
+// is left running in the background when ctx is done, and maxChecks only
+// tells if it took too many checks. v0 panics when it exhausts the search,
+// which is reported as a board without solution.
+func solveV0(ctx context.Context, tiles [][]TileType, maxChecks int64) (Result, error) {
+	if _, err := ParseBoard(tiles); err != nil {
+		return Result{}, err
+	}
+	defer silenceStdout()()
+	v0Tiles := make([][]v0.TileType, len(tiles))
+	for x, line := range tiles {
+		for _, tile := range line {
+			v0Tiles[x] = append(v0Tiles[x], v0.TileType(tile))
+		}
+	}
+
+	type done struct {
+		actions []v0.Action
+		n       int64
+		dur     time.Duration
+	}
+	c := make(chan done, 1)
+	go func() {
+		start := time.Now()
+		defer func() {
+			if recover() != nil {
+				c <- done{dur: time.Since(start)}
+			}
+		}()
+		board := v0.NewBoard(v0Tiles)
+		actions, n := board.Solve()
+		c <- done{actions, n, time.Since(start)}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return Result{}, ctx.Err()
+	case d := <-c:
+		if maxChecks > 0 && d.n > maxChecks {
+			return Result{Checks: d.n, Duration: d.dur}, ErrSearchLimit
+		}
+		result := Result{Checks: d.n, Duration: d.dur}
+		for _, a := range d.actions {
+			result.Actions = append(result.Actions, Action{X1: a.X1, Y1: a.Y1, X2: a.X2, Y2: a.Y2, Type1: TileType(a.Type1), Type2: TileType(a.Type2)})
+		}
+		return result, nil
+	}
+}
+
+// stdoutMutex serializes the v0 searches, which print their progress on the
+// standard output every 100000 checks.
+var stdoutMutex sync.Mutex
+
+// silenceStdout sends the standard output to os.DevNull until the returned
+// function is called. A v0 search left running after its context is done
+// prints again once the output is restored.
+func silenceStdout() func() {
+	stdoutMutex.Lock()
+	stdout := os.Stdout
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		return stdoutMutex.Unlock
+	}
+	os.Stdout = devNull
+	return func() {
+		os.Stdout = stdout
+		devNull.Close()
+		stdoutMutex.Unlock()
+	}
+}
+
+var ErrNotSolved = errors.New("tiles are left after the last move")
+
+// Verify replays the actions on the tiles with the rules of the current
+// solver, returning why they do not solve the board.
+func Verify(tiles [][]TileType, actions []Action) error {
+	board, err := ParseBoard(tiles)
+	if err != nil {
+		return err
+	}
+	for i, action := range actions {
+		if _, err := board.Move(action.X1, action.Y1, action.X2, action.Y2); err != nil {
+			return fmt.Errorf("move %d: %w", i+1, err)
+		}
+	}
+	for _, tile := range board.Board {
+		if tile.Type != TileType_EMPTY {
+			return ErrNotSolved
+		}
+	}
+	return nil
+}
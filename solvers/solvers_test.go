@@ -0,0 +1,18 @@
+package solvers
+
+import (
+	"testing"
+	"time"
+)
+
+// only the unbounded solvers are refused, and only with a timeout
+func TestBounded(t *testing.T) {
+	for _, solver := range All {
+		for _, limits := range []Limits{{}, {MaxChecks: 1000}, {Timeout: time.Second}} {
+			err := solver.Bounded(limits)
+			if want := solver.Unbounded && limits.Timeout > 0; (err != nil) != want {
+				t.Errorf("%s with %+v: %v", solver.Name, limits, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,139 @@
+#!/bin/sh
+# step 1: key l1
+xdotool mousemove 1131 342 click 1
+sleep 0.1
+xdotool mousemove 731 441 click 1
+sleep 0.1
+# step 2: light dark
+xdotool mousemove 1160 392 click 1
+sleep 0.1
+xdotool mousemove 903 342 click 1
+sleep 0.1
+# step 3: key l2
+xdotool mousemove 989 788 click 1
+sleep 0.1
+xdotool mousemove 1017 342 click 1
+sleep 0.1
+# step 4: cyan cyan
+xdotool mousemove 931 293 click 1
+sleep 0.1
+xdotool mousemove 760 491 click 1
+sleep 0.1
+# step 5: blue blue
+xdotool mousemove 817 392 click 1
+sleep 0.1
+xdotool mousemove 789 441 click 1
+sleep 0.1
+# step 6: white orange
+xdotool mousemove 846 441 click 1
+sleep 0.1
+xdotool mousemove 1103 392 click 1
+sleep 0.1
+# step 7: blue blue
+xdotool mousemove 903 441 click 1
+sleep 0.1
+xdotool mousemove 1131 441 click 1
+sleep 0.1
+# step 8: light dark
+xdotool mousemove 703 491 click 1
+sleep 0.1
+xdotool mousemove 960 342 click 1
+sleep 0.1
+# step 9: green green
+xdotool mousemove 989 392 click 1
+sleep 0.1
+xdotool mousemove 1160 491 click 1
+sleep 0.1
+# step 10: light dark
+xdotool mousemove 1017 441 click 1
+sleep 0.1
+xdotool mousemove 1103 491 click 1
+sleep 0.1
+# step 11: white orange
+xdotool mousemove 1046 491 click 1
+sleep 0.1
+xdotool mousemove 874 491 click 1
+sleep 0.1
+# step 12: white green
+xdotool mousemove 846 540 click 1
+sleep 0.1
+xdotool mousemove 760 590 click 1
+sleep 0.1
+# step 13: orange orange
+xdotool mousemove 1074 540 click 1
+sleep 0.1
+xdotool mousemove 817 590 click 1
+sleep 0.1
+# step 14: blue blue
+xdotool mousemove 789 639 click 1
+sleep 0.1
+xdotool mousemove 903 738 click 1
+sleep 0.1
+# step 15: green green
+xdotool mousemove 874 293 click 1
+sleep 0.1
+xdotool mousemove 1160 590 click 1
+sleep 0.1
+# step 16: light dark
+xdotool mousemove 817 689 click 1
+sleep 0.1
+xdotool mousemove 1189 639 click 1
+sleep 0.1
+# step 17: orange orange
+xdotool mousemove 874 590 click 1
+sleep 0.1
+xdotool mousemove 1131 639 click 1
+sleep 0.1
+# step 18: cyan cyan
+xdotool mousemove 903 540 click 1
+sleep 0.1
+xdotool mousemove 903 639 click 1
+sleep 0.1
+# step 19: cyan cyan
+xdotool mousemove 931 590 click 1
+sleep 0.1
+xdotool mousemove 760 689 click 1
+sleep 0.1
+# step 20: green green
+xdotool mousemove 1217 590 click 1
+sleep 0.1
+xdotool mousemove 960 738 click 1
+sleep 0.1
+# step 21: cyan cyan
+xdotool mousemove 1103 689 click 1
+sleep 0.1
+xdotool mousemove 1017 738 click 1
+sleep 0.1
+# step 22: white green
+xdotool mousemove 1074 639 click 1
+sleep 0.1
+xdotool mousemove 1046 788 click 1
+sleep 0.1
+# step 23: key l3
+xdotool mousemove 1017 639 click 1
+sleep 0.1
+xdotool mousemove 960 441 click 1
+sleep 0.1
+# step 24: key l4
+xdotool mousemove 989 491 click 1
+sleep 0.1
+xdotool mousemove 931 491 click 1
+sleep 0.1
+# step 25: orange orange
+xdotool mousemove 1046 590 click 1
+sleep 0.1
+xdotool mousemove 931 689 click 1
+sleep 0.1
+# step 26: blue blue
+xdotool mousemove 960 639 click 1
+sleep 0.1
+xdotool mousemove 789 738 click 1
+sleep 0.1
+# step 27: key l5
+xdotool mousemove 989 590 click 1
+sleep 0.1
+xdotool mousemove 1017 540 click 1
+sleep 0.1
+# step 28: l6
+xdotool mousemove 960 540 click 1
+sleep 0.1
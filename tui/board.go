@@ -0,0 +1,137 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	. "sigmars-garden-solver/internal/srcs"
+)
+
+type Mark int
+
+const (
+	Mark_NONE Mark = iota
+	Mark_SELECTED
+	Mark_UNLOCKED
+	Mark_CURSOR
+	Mark_SAFE
+	Mark_LOSING
+	Mark_PROBLEM
+)
+
+// Marks associates positions of the board with the way they are highlighted.
+type Marks map[Position]Mark
+
+const (
+	ansiReset   = "\033[0m"
+	ansiDim     = "\033[2m"
+	ansiReverse = "\033[7m"
+	ansiClear   = "\033[H\033[2J"
+)
+
+var tileSymbols = map[TileType]string{
+	TileType_EMPTY:  "..",
+	TileType_WHITE:  "wh",
+	TileType_CYAN:   "cy",
+	TileType_ORANGE: "or",
+	TileType_BLUE:   "bl",
+	TileType_GREEN:  "gr",
+	TileType_LIGHT:  "li",
+	TileType_DARK:   "da",
+	TileType_KEY:    "ke",
+	TileType_L1:     "l1",
+	TileType_L2:     "l2",
+	TileType_L3:     "l3",
+	TileType_L4:     "l4",
+	TileType_L5:     "l5",
+	TileType_L6:     "l6",
+}
+
+var tileColors = map[TileType]string{
+	TileType_WHITE:  "\033[97m",
+	TileType_CYAN:   "\033[36m",
+	TileType_ORANGE: "\033[31m",
+	TileType_BLUE:   "\033[34m",
+	TileType_GREEN:  "\033[32m",
+	TileType_LIGHT:  "\033[93m",
+	TileType_DARK:   "\033[35m",
+	TileType_KEY:    "\033[37m",
+	TileType_L1:     "\033[90m",
+	TileType_L2:     "\033[90m",
+	TileType_L3:     "\033[90m",
+	TileType_L4:     "\033[90m",
+	TileType_L5:     "\033[90m",
+	TileType_L6:     "\033[33m",
+}
+
+var markBrackets = map[Mark][2]string{
+	Mark_NONE:     {" ", " "},
+	Mark_SELECTED: {"[", "]"},
+	Mark_UNLOCKED: {"+", "+"},
+	Mark_CURSOR:   {">", "<"},
+	Mark_SAFE:     {"(", ")"},
+	Mark_LOSING:   {"!", "!"},
+	Mark_PROBLEM:  {"#", "#"},
+}
+
+var markColors = map[Mark]string{
+	Mark_SELECTED: "\033[1;93m",
+	Mark_UNLOCKED: "\033[1;96m",
+	Mark_CURSOR:   "\033[1;97m",
+	Mark_SAFE:     "\033[1;92m",
+	Mark_LOSING:   "\033[1;91m",
+	Mark_PROBLEM:  "\033[1;91m",
+}
+
+// TileSymbol returns the two letters used to draw a tile type.
+func TileSymbol(tileType TileType) string {
+	if s, ok := tileSymbols[tileType]; ok {
+		return s
+	}
+	return "??"
+}
+
+// RenderBoard draws the board as text, one line per row of the hexagon.
+// Locked tiles are dimmed and marked positions are surrounded by the mark
+// brackets. Colors are only emitted when color is true.
+func RenderBoard(board *Board, marks Marks, color bool) string {
+	var sb strings.Builder
+
+	for x := 0; IsPossitionValid(x, 0); x++ {
+		indent := x - 5
+		if indent < 0 {
+			indent = -indent
+		}
+		fmt.Fprintf(&sb, "%2d %s", x, strings.Repeat("  ", indent))
+		for y := 0; IsPossitionValid(x, y); y++ {
+			tile := board.Board[FromXYPos(x, y)]
+			mark := marks[Position{X: x, Y: y}]
+			brackets := markBrackets[mark]
+			symbol := TileSymbol(tile.Type)
+
+			if color {
+				if c, ok := markColors[mark]; ok {
+					brackets[0], brackets[1] = c+brackets[0]+ansiReset, c+brackets[1]+ansiReset
+				}
+				style := tileColors[tile.Type]
+				if tile.Lock {
+					style += ansiDim
+				}
+				if mark == Mark_SELECTED || mark == Mark_CURSOR {
+					style += ansiReverse
+				}
+				symbol = style + symbol + ansiReset
+			} else if tile.Type != TileType_EMPTY && !tile.Lock {
+				symbol = strings.ToUpper(symbol)
+			}
+			sb.WriteString(brackets[0] + symbol + brackets[1])
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// ActionToString formats a single action the same way SolutionToString does.
+func ActionToString(action Action) string {
+	return strings.TrimSuffix(SolutionToString([]Action{action}), "\n")
+}
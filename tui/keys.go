@@ -0,0 +1,116 @@
+package tui
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+type Key int
+
+const (
+	Key_NONE Key = iota
+	Key_ENTER
+	Key_UP
+	Key_DOWN
+	Key_LEFT
+	Key_RIGHT
+	Key_SELECT
+	Key_QUIT
+	Key_RUNE
+)
+
+// KeyEvent is a key read from the terminal. Rune is set for every printable
+// key so that callers can handle their own bindings.
+type KeyEvent struct {
+	Key  Key
+	Rune rune
+}
+
+// KeyReader reads single key presses. When the input is a terminal it is put
+// in raw mode with stty, otherwise keys are read as they come (one per rune,
+// newlines included) which keeps piped input usable.
+type KeyReader struct {
+	reader   *bufio.Reader
+	file     *os.File
+	sttyMode string
+}
+
+func NewKeyReader(in io.Reader) *KeyReader {
+	kr := &KeyReader{reader: bufio.NewReader(in)}
+
+	if f, ok := in.(*os.File); ok && isTerminal(f) {
+		cmd := exec.Command("stty", "-g")
+		cmd.Stdin = f
+		if out, err := cmd.Output(); err == nil {
+			kr.file = f
+			kr.sttyMode = strings.TrimSpace(string(out))
+			kr.stty("-icanon", "-echo", "min", "1")
+		}
+	}
+	return kr
+}
+
+func isTerminal(f *os.File) bool {
+	stat, err := f.Stat()
+	return err == nil && stat.Mode()&os.ModeCharDevice != 0
+}
+
+func (this *KeyReader) stty(args ...string) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = this.file
+	cmd.Run()
+}
+
+// Close restores the terminal mode.
+func (this *KeyReader) Close() {
+	if this.file != nil {
+		this.stty(this.sttyMode)
+		this.file = nil
+	}
+}
+
+// IsRaw reports if keys are received one at a time.
+func (this *KeyReader) IsRaw() bool {
+	return this.file != nil
+}
+
+// ReadKey blocks until a key is pressed.
+func (this *KeyReader) ReadKey() (KeyEvent, error) {
+	r, _, err := this.reader.ReadRune()
+	if err != nil {
+		return KeyEvent{}, err
+	}
+
+	switch r {
+	case '\033':
+		if this.reader.Buffered() < 2 {
+			return KeyEvent{Key: Key_QUIT, Rune: r}, nil
+		}
+		seq := make([]byte, 2)
+		io.ReadFull(this.reader, seq)
+		if seq[0] == '[' {
+			switch seq[1] {
+			case 'A':
+				return KeyEvent{Key: Key_UP}, nil
+			case 'B':
+				return KeyEvent{Key: Key_DOWN}, nil
+			case 'C':
+				return KeyEvent{Key: Key_RIGHT}, nil
+			case 'D':
+				return KeyEvent{Key: Key_LEFT}, nil
+			}
+		}
+		return KeyEvent{}, nil
+	case '\n', '\r':
+		return KeyEvent{Key: Key_ENTER, Rune: r}, nil
+	case ' ':
+		return KeyEvent{Key: Key_SELECT, Rune: r}, nil
+	case 'q', 'Q', 3, 4:
+		return KeyEvent{Key: Key_QUIT, Rune: r}, nil
+	default:
+		return KeyEvent{Key: Key_RUNE, Rune: r}, nil
+	}
+}
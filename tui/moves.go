@@ -0,0 +1,58 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	. "sigmars-garden-solver/internal/srcs"
+)
+
+// MoveMarks marks the tiles of the moves: safe if one of their pairs keeps
+// the board solvable, losing if all of them lose. Tiles whose moves are
+// undecided are left unmarked.
+func MoveMarks(moves []MoveSafety) Marks {
+	marks := Marks{}
+	undecided := map[Position]bool{}
+	for _, move := range moves {
+		action := move.Action
+		for _, pos := range []Position{{X: action.X1, Y: action.Y1}, {X: action.X2, Y: action.Y2}} {
+			switch move.Outcome {
+			case Outcome_WIN:
+				marks[pos] = Mark_SAFE
+			case Outcome_LOSS:
+				if marks[pos] == Mark_NONE {
+					marks[pos] = Mark_LOSING
+				}
+			default:
+				undecided[pos] = true
+			}
+		}
+	}
+	for pos := range undecided {
+		if marks[pos] == Mark_LOSING {
+			delete(marks, pos)
+		}
+	}
+	return marks
+}
+
+// RenderMoves draws the board with the tiles marked by MoveMarks, followed
+// by the list of the moves and their outcome.
+func RenderMoves(board *Board, moves []MoveSafety, color bool) string {
+	var sb strings.Builder
+	sb.WriteString(RenderBoard(board, MoveMarks(moves), color))
+
+	labels := map[Outcome]string{Outcome_WIN: "safe", Outcome_LOSS: "losing", Outcome_UNKNOWN: "unknown"}
+	marks := map[Outcome]Mark{Outcome_WIN: Mark_SAFE, Outcome_LOSS: Mark_LOSING}
+	for _, move := range moves {
+		label := labels[move.Outcome]
+		if c, ok := markColors[marks[move.Outcome]]; ok && color {
+			label = c + label + ansiReset
+		}
+		fmt.Fprintf(&sb, "%-7s %s\n", label, strings.TrimSpace(ActionToString(move.Action)))
+	}
+	if len(moves) == 0 {
+		sb.WriteString("no legal move\n")
+	}
+	return sb.String()
+}
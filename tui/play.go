@@ -0,0 +1,270 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	. "sigmars-garden-solver/internal/srcs"
+)
+
+type PlayOptions struct {
+	Color     bool
+	MaxChecks int64 // checks allowed to the solver for a hint, see Oracle
+}
+
+// game is the state of a Play session.
+type game struct {
+	board    Board
+	oracle   *Oracle
+	history  []Action // moves played, undone from the end
+	redo     []Action // moves undone, replayed from the end
+	cursor   Position
+	selected []Position
+	hint     []Position
+	message  string
+}
+
+// Play lets the user solve the board. Tiles are selected with the cursor
+// (arrows, then space or enter) or by typing their coordinates "x y" then
+// enter. Two selected tiles are removed if the rules allow it, the last
+// metal is removed by selecting it twice. The solver gives a hint or tells
+// if the board is still solvable on demand.
+func Play(board Board, in io.Reader, out io.Writer, opts PlayOptions) error {
+	keys := NewKeyReader(in)
+	defer keys.Close()
+
+	g := &game{
+		board:  board,
+		oracle: NewOracle(opts.MaxChecks),
+		cursor: Position{X: 5, Y: 5},
+	}
+	var typed string
+	lineStarted := false
+	redraw := true
+
+	for {
+		if redraw {
+			g.draw(out, typed, opts, keys.IsRaw())
+		}
+		// typed characters are echoed by the terminal without raw mode
+		redraw = keys.IsRaw()
+
+		key, err := keys.ReadKey()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		// without raw mode a newline only ends the line of a command
+		if key.Key == Key_ENTER && lineStarted && typed == "" {
+			lineStarted = false
+			redraw = false
+			continue
+		}
+		lineStarted = !keys.IsRaw() && key.Key != Key_ENTER
+
+		switch {
+		case key.Key == Key_QUIT:
+			return nil
+		case key.Rune >= '0' && key.Rune <= '9', key.Rune == ',' && typed != "", key.Key == Key_SELECT && typed != "":
+			typed += string(key.Rune)
+			continue
+		case key.Rune == 127 || key.Rune == '\b':
+			if typed != "" {
+				typed = typed[:len(typed)-1]
+			}
+			continue
+		}
+
+		g.message = ""
+		redraw = true
+		switch {
+		case key.Key == Key_ENTER && typed != "":
+			if pos, ok := parsePosition(typed); ok {
+				g.cursor = pos
+				g.selectTile(pos)
+			} else {
+				g.message = fmt.Sprintf("%q is not a position, type \"x y\"", typed)
+			}
+			typed = ""
+		case key.Key == Key_ENTER, key.Key == Key_SELECT:
+			g.selectTile(g.cursor)
+		case key.Key == Key_UP:
+			g.moveCursor(-1, 0)
+		case key.Key == Key_DOWN:
+			g.moveCursor(1, 0)
+		case key.Key == Key_LEFT:
+			g.moveCursor(0, -1)
+		case key.Key == Key_RIGHT:
+			g.moveCursor(0, 1)
+		case key.Rune == 'u':
+			g.undo()
+		case key.Rune == 'r':
+			g.redoMove()
+		case key.Rune == 'h':
+			g.giveHint()
+		case key.Rune == 's':
+			g.checkSolvable()
+		}
+	}
+}
+
+// parsePosition reads "x y" or "x,y".
+func parsePosition(s string) (Position, bool) {
+	fields := strings.FieldsFunc(s, func(r rune) bool { return r == ' ' || r == ',' })
+	if len(fields) != 2 {
+		return Position{}, false
+	}
+	x, errX := strconv.Atoi(fields[0])
+	y, errY := strconv.Atoi(fields[1])
+	if errX != nil || errY != nil || !IsPossitionValid(x, y) {
+		return Position{}, false
+	}
+	return Position{X: x, Y: y}, true
+}
+
+// moveCursor moves the cursor by rows and columns, keeping it on the board.
+func (this *game) moveCursor(dx, dy int) {
+	x, y := this.cursor.X+dx, this.cursor.Y+dy
+	if !IsPossitionValid(x, 0) {
+		return
+	}
+	for y > 0 && !IsPossitionValid(x, y) {
+		y--
+	}
+	if y < 0 {
+		y = 0
+	}
+	this.cursor = Position{X: x, Y: y}
+}
+
+func (this *game) selectTile(pos Position) {
+	tile := this.board.Board[FromXYPos(pos.X, pos.Y)]
+	if len(this.selected) == 0 {
+		if tile.Type == TileType_EMPTY {
+			this.message = fmt.Sprintf("(%d,%d) is empty", pos.X, pos.Y)
+			return
+		} else if tile.Lock {
+			this.message = fmt.Sprintf("%s (%d,%d) is locked", tile.Type, pos.X, pos.Y)
+			return
+		}
+		this.selected = []Position{pos}
+		return
+	}
+
+	first := this.selected[0]
+	this.selected = nil
+	if first == pos && tile.Type != TileType_L6 {
+		return
+	}
+	action, err := this.board.Move(first.X, first.Y, pos.X, pos.Y)
+	if err != nil {
+		this.message = err.Error()
+		return
+	}
+	this.history = append(this.history, action)
+	this.redo = nil
+	this.hint = nil
+	if this.isWon() {
+		this.message = fmt.Sprintf("solved in %d moves", len(this.history))
+	}
+}
+
+func (this *game) undo() {
+	if len(this.history) == 0 {
+		this.message = "nothing to undo"
+		return
+	}
+	action := this.history[len(this.history)-1]
+	this.board.UndoAction(action)
+	this.history = this.history[:len(this.history)-1]
+	this.redo = append(this.redo, action)
+	this.selected, this.hint = nil, nil
+}
+
+func (this *game) redoMove() {
+	if len(this.redo) == 0 {
+		this.message = "nothing to redo"
+		return
+	}
+	undone := this.redo[len(this.redo)-1]
+	this.redo = this.redo[:len(this.redo)-1]
+	this.history = append(this.history, this.board.ApplyAction(undone.X1, undone.Y1, undone.X2, undone.Y2))
+	this.selected, this.hint = nil, nil
+}
+
+func (this *game) giveHint() {
+	this.hint = nil
+	undecided := false
+	for _, move := range this.oracle.Moves(this.board) {
+		if move.Outcome == Outcome_WIN {
+			action := move.Action
+			this.hint = []Position{{X: action.X1, Y: action.Y1}, {X: action.X2, Y: action.Y2}}
+			this.message = "hint: " + strings.TrimSpace(ActionToString(action))
+			return
+		}
+		undecided = undecided || move.Outcome == Outcome_UNKNOWN
+	}
+	if this.isWon() {
+		this.message = "the board is solved"
+	} else if undecided {
+		this.message = "no hint found within the search limit"
+	} else {
+		this.message = "every move loses, undo"
+	}
+}
+
+func (this *game) checkSolvable() {
+	switch this.oracle.Solvable(this.board) {
+	case Outcome_WIN:
+		this.message = "the board is still solvable"
+	case Outcome_LOSS:
+		this.message = "the board cannot be solved anymore, undo"
+	default:
+		this.message = "unknown, the search limit was reached"
+	}
+}
+
+func (this *game) isWon() bool {
+	for _, tile := range this.board.Board {
+		if tile.Type != TileType_EMPTY {
+			return false
+		}
+	}
+	return true
+}
+
+func (this *game) draw(out io.Writer, typed string, opts PlayOptions, raw bool) {
+	marks := Marks{}
+	if len(this.history) > 0 {
+		for _, pos := range this.history[len(this.history)-1].Unlocked {
+			marks[pos] = Mark_UNLOCKED
+		}
+	}
+	for _, pos := range this.hint {
+		marks[pos] = Mark_SAFE
+	}
+	marks[this.cursor] = Mark_CURSOR
+	for _, pos := range this.selected {
+		marks[pos] = Mark_SELECTED
+	}
+
+	var sb strings.Builder
+	if opts.Color && raw {
+		sb.WriteString(ansiClear)
+	}
+	fmt.Fprintf(&sb, "move %d\n", len(this.history)+1)
+	sb.WriteString(RenderBoard(&this.board, marks, opts.Color))
+	if this.message != "" {
+		sb.WriteString(this.message + "\n")
+	}
+	sb.WriteString("[arrows] move  [space/enter] select  [x y enter] select (x,y)  [u] undo  [r] redo  [h] hint  [s] solvable?  [q] quit\n")
+	if typed != "" {
+		sb.WriteString("> " + typed + "\n")
+	}
+	sb.WriteString("\n")
+	fmt.Fprint(out, sb.String())
+}
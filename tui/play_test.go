@@ -0,0 +1,83 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	. "sigmars-garden-solver/internal/srcs"
+)
+
+// two salts, one water and one fire: pairing the salts together loses
+func saltsBoard() Board {
+	lines := [][]TileType{}
+	for x := 0; x < 11; x++ {
+		size := 6 + x
+		if x > 5 {
+			size = 16 - x
+		}
+		lines = append(lines, make([]TileType, size))
+	}
+	lines[0][0], lines[0][5] = TileType_WHITE, TileType_WHITE
+	lines[10][0], lines[10][5] = TileType_CYAN, TileType_ORANGE
+	return NewBoard(lines)
+}
+
+// play types the script and checks the messages appear in order
+func play(t *testing.T, script string, messages ...string) {
+	t.Helper()
+	var out strings.Builder
+	if err := Play(saltsBoard(), strings.NewReader(script), &out, PlayOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	rest := out.String()
+	for _, message := range messages {
+		i := strings.Index(rest, message)
+		if i < 0 {
+			t.Fatalf("%q not found after the previous messages:\n%s", message, out.String())
+		}
+		rest = rest[i+len(message):]
+	}
+}
+
+func TestPlaySelect(t *testing.T) {
+	play(t, "5 5\n12 3\n0 0\n10 0\n10 5\n0 5\nq\n",
+		"(5,5) is empty",
+		`"12 3" is not a position`,
+		"move 2",
+		"solved in 2 moves")
+}
+
+func TestPlayUndoRedo(t *testing.T) {
+	play(t, "u\nr\n0 0\n0 5\nu\nu\nr\nr\nq\n",
+		"nothing to undo",
+		"nothing to redo",
+		"move 2",
+		"move 1",
+		"nothing to undo",
+		"move 2",
+		"nothing to redo")
+}
+
+func TestPlaySolvable(t *testing.T) {
+	play(t, "s\n0 0\n0 5\ns\nh\nu\ns\nq\n",
+		"the board is still solvable",
+		"the board cannot be solved anymore, undo",
+		"every move loses, undo",
+		"the board is still solvable")
+}
+
+// the hint is a winning move, not the pair of salts
+func TestPlayHint(t *testing.T) {
+	var out strings.Builder
+	if err := Play(saltsBoard(), strings.NewReader("h\nq\n"), &out, PlayOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	i := strings.Index(out.String(), "hint: ")
+	if i < 0 {
+		t.Fatalf("no hint:\n%s", out.String())
+	}
+	hint := strings.SplitN(out.String()[i:], "\n", 2)[0]
+	if strings.Count(hint, string(TileType_WHITE)) != 1 {
+		t.Errorf("%q, want a salt paired with an element", hint)
+	}
+}
@@ -0,0 +1,130 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	. "sigmars-garden-solver/internal/srcs"
+)
+
+type ReplayOptions struct {
+	Delay time.Duration // advance automatically after Delay, 0 to wait for a key
+	Color bool
+}
+
+// Replay steps through a solution of board. Before each action the board is
+// drawn with the pair to click highlighted and the positions unlocked by the
+// previous action marked. Going back uses Board.UndoAction, like the solver.
+func Replay(board Board, actions []Action, in io.Reader, out io.Writer, opts ReplayOptions) error {
+	keys := NewKeyReader(in)
+	defer keys.Close()
+
+	// the reader stops at the first key read once Replay returned, a read
+	// in progress cannot be interrupted
+	events := make(chan KeyEvent)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		defer close(events)
+		for {
+			key, err := keys.ReadKey()
+			if err != nil {
+				return
+			}
+			select {
+			case events <- key:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	applied := make([]Action, 0, len(actions))
+	inputOpen := true
+	lineStarted := false
+
+	for {
+		draw(out, &board, actions, applied, opts, keys.IsRaw())
+
+		var timer <-chan time.Time
+		if opts.Delay > 0 && len(applied) < len(actions) {
+			timer = time.After(opts.Delay)
+		}
+		if timer == nil && !inputOpen {
+			return nil
+		}
+
+		step := 0
+		for step == 0 {
+			select {
+			case <-timer:
+				step = 1
+			case key, ok := <-events:
+				if !ok {
+					inputOpen = false
+					events = nil
+					if timer == nil {
+						return nil
+					}
+					continue
+				}
+				// without raw mode a newline only ends the line of a command
+				if key.Key == Key_ENTER && lineStarted {
+					lineStarted = false
+					continue
+				}
+				lineStarted = !keys.IsRaw() && key.Key != Key_ENTER
+
+				switch {
+				case key.Key == Key_QUIT:
+					return nil
+				case key.Key == Key_ENTER, key.Key == Key_SELECT, key.Key == Key_RIGHT, key.Rune == 'n', key.Rune == 'l':
+					step = 1
+				case key.Key == Key_LEFT, key.Rune == 'p', key.Rune == 'b', key.Rune == 'h':
+					step = -1
+				}
+			}
+		}
+
+		if step > 0 && len(applied) < len(actions) {
+			a := actions[len(applied)]
+			applied = append(applied, board.ApplyAction(a.X1, a.Y1, a.X2, a.Y2))
+		} else if step < 0 && len(applied) > 0 {
+			board.UndoAction(applied[len(applied)-1])
+			applied = applied[:len(applied)-1]
+		} else if step > 0 && opts.Delay == 0 {
+			return nil
+		}
+	}
+}
+
+func draw(out io.Writer, board *Board, actions, applied []Action, opts ReplayOptions, raw bool) {
+	marks := Marks{}
+	if len(applied) > 0 {
+		for _, pos := range applied[len(applied)-1].Unlocked {
+			marks[pos] = Mark_UNLOCKED
+		}
+	}
+
+	var sb strings.Builder
+	if opts.Color && raw {
+		sb.WriteString(ansiClear)
+	}
+
+	if len(applied) < len(actions) {
+		next := actions[len(applied)]
+		marks[Position{X: next.X1, Y: next.Y1}] = Mark_SELECTED
+		marks[Position{X: next.X2, Y: next.Y2}] = Mark_SELECTED
+		fmt.Fprintf(&sb, "step %d/%d: %s\n", len(applied)+1, len(actions), ActionToString(next))
+	} else {
+		fmt.Fprintf(&sb, "solved in %d steps\n", len(actions))
+	}
+	sb.WriteString(RenderBoard(board, marks, opts.Color))
+	if len(applied) > 0 && len(applied[len(applied)-1].Unlocked) > 0 {
+		fmt.Fprintf(&sb, "unlocked: %v\n", applied[len(applied)-1].Unlocked)
+	}
+	sb.WriteString("[enter/n] next  [p/b] previous  [q] quit\n\n")
+	fmt.Fprint(out, sb.String())
+}
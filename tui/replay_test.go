@@ -0,0 +1,54 @@
+package tui
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	. "sigmars-garden-solver/internal/srcs"
+)
+
+func loadBoard(t *testing.T, path string) Board {
+	t.Helper()
+	byteValue, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tiles [][]TileType
+	if err := json.Unmarshal(byteValue, &tiles); err != nil {
+		t.Fatal(err)
+	}
+	return NewBoard(tiles)
+}
+
+// keys typed after q are left to the reader, which must not wait forever
+// to send them
+func TestReplayStopsReader(t *testing.T) {
+	board := loadBoard(t, "../inputs/input1.json")
+	solved := board.Clone()
+	actions, _, _ := solved.Solve()
+	if len(actions) == 0 {
+		t.Fatal("input1 not solved")
+	}
+
+	before := runtime.NumGoroutine()
+	var out strings.Builder
+	in := strings.NewReader("n\nn\nq\nn\nn\n")
+	if err := Replay(board, actions, in, &out, ReplayOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "step 3/") {
+		t.Errorf("two steps were not replayed:\n%s", out.String())
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d goroutines after Replay, %d before", runtime.NumGoroutine(), before)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	. "sigmars-garden-solver/internal/srcs"
+)
+
+type problemOutput struct {
+	Cause   string     `json:"cause"`
+	Message string     `json:"message"`
+	Tiles   []Position `json:"tiles"`
+}
+
+type validateOutput struct {
+	Valid    bool            `json:"valid"`
+	Problems []problemOutput `json:"problems"`
+}
+
+// validateMain checks the shape and the inventory of a board, exiting with
+// ExitCode_UNSOLVABLE if a problem is found.
+func validateMain(args []string) {
+	flags := newFlagSet("validate")
+	common := addBoardFlags(flags)
+	flags.Parse(args)
+	common.check()
+
+	board := loadBoard(inputArg(flags))
+	deadEnds := CheckInventory(board)
+	output := validateOutput{Valid: len(deadEnds) == 0, Problems: []problemOutput{}}
+	for _, deadEnd := range deadEnds {
+		output.Problems = append(output.Problems, problemOutput{deadEnd.Cause.String(), deadEnd.Message, deadEnd.Tiles})
+	}
+
+	switch {
+	case common.json():
+		printJSON(output)
+	case *common.quiet:
+	case output.Valid:
+		fmt.Println("no problem found")
+	default:
+		for _, deadEnd := range deadEnds {
+			fmt.Println(deadEnd)
+		}
+	}
+	if !output.Valid {
+		os.Exit(int(ExitCode_UNSOLVABLE))
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	. "sigmars-garden-solver/internal/srcs"
+)
+
+type verifyOutput struct {
+	Valid   bool   `json:"valid"`
+	Played  int    `json:"played"` // moves played before the first illegal one
+	Problem string `json:"problem,omitempty"`
+}
+
+// verifyMain replays a solution on a board, exiting with ExitCode_UNSOLVABLE
+// if a move is illegal or if tiles are left at the end.
+func verifyMain(args []string) {
+	flags := newFlagSet("verify")
+	solutionPath := flags.String("solution", "", "solution to check, as written by solve -format json or as a list of moves")
+	common := addBoardFlags(flags)
+	flags.Parse(args)
+	common.check()
+
+	if *solutionPath == "" {
+		flags.Usage()
+		os.Exit(int(ExitCode_INVALID))
+	}
+	board := loadBoard(inputArg(flags))
+	moves, err := readMoves(*solutionPath)
+	if err != nil {
+		fail(ExitCode_INVALID, "%v", err)
+	}
+
+	output := verifyOutput{Valid: true}
+	for i, move := range moves {
+		if _, err := board.Move(move.X1, move.Y1, move.X2, move.Y2); err != nil {
+			output = verifyOutput{Played: i, Problem: fmt.Sprintf("move %d: %v", i+1, err)}
+			break
+		}
+		output.Played++
+	}
+	if output.Valid && !isCleared(board) {
+		output = verifyOutput{Played: output.Played, Problem: "tiles are left after the last move"}
+	}
+
+	switch {
+	case common.json():
+		printJSON(output)
+	case *common.quiet:
+	case output.Valid:
+		fmt.Printf("the %d moves solve the board\n", output.Played)
+	default:
+		fmt.Println(output.Problem)
+	}
+	if !output.Valid {
+		os.Exit(int(ExitCode_UNSOLVABLE))
+	}
+}
+
+// readMoves reads the moves of a solution file, see readInput.
+func readMoves(path string) ([]Action, error) {
+	byteValue, err := readInput(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var moves []Action
+	if err := json.Unmarshal(byteValue, &moves); err == nil {
+		return moves, nil
+	}
+	var output solveOutput
+	if err := json.Unmarshal(byteValue, &output); err != nil {
+		return nil, fmt.Errorf("%s: %w", inputName(path), err)
+	}
+	return output.Moves, nil
+}
@@ -0,0 +1,56 @@
+//go:build ignore
+
+// genscreenshot draws the screenshot of testdata: the board of
+// inputs/input1.json at the place of the default 1920x1080 calibration, a few
+// pixels away from it as on a real screen.
+package main
+
+import (
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io/ioutil"
+	"math"
+	"os"
+
+	. "sigmars-garden-solver/internal/srcs"
+	"sigmars-garden-solver/render"
+	"sigmars-garden-solver/screen"
+)
+
+const offsetX, offsetY = 4, -3
+
+func main() {
+	byteValue, err := ioutil.ReadFile("../inputs/input1.json")
+	if err != nil {
+		panic(err)
+	}
+	var tiles [][]TileType
+	if err := json.Unmarshal(byteValue, &tiles); err != nil {
+		panic(err)
+	}
+	board := NewBoard(tiles)
+
+	cal := screen.Default(1920, 1080)
+	img := image.NewRGBA(image.Rect(0, 0, cal.Width, cal.Height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{color.RGBA{0x2b, 0x24, 0x1d, 0xff}}, image.Point{}, draw.Src)
+
+	drawn := render.Image(&board, render.Options{HexSize: cal.HexSize})
+	size := drawn.Bounds().Size()
+	at := image.Pt(
+		int(math.Round(cal.OriginX-float64(size.X)/2))+offsetX,
+		int(math.Round(cal.OriginY-float64(size.Y)/2))+offsetY,
+	)
+	draw.Draw(img, image.Rectangle{at, at.Add(size)}, drawn, image.Point{}, draw.Src)
+
+	f, err := os.Create("testdata/screenshot-1920x1080.png")
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+	if err := png.Encode(f, img); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,51 @@
+//go:build ignore
+
+// gensprites draws the bundled sprites with the render package. They are a
+// stand-in for captures of the game, see Learn to replace them.
+package main
+
+import (
+	"image"
+	"image/draw"
+
+	. "sigmars-garden-solver/internal/srcs"
+	"sigmars-garden-solver/render"
+	"sigmars-garden-solver/screen"
+	"sigmars-garden-solver/vision"
+)
+
+func main() {
+	sprites := vision.Sprites{}
+	for _, tileType := range []TileType{
+		TileType_EMPTY, TileType_WHITE, TileType_CYAN, TileType_ORANGE, TileType_BLUE, TileType_GREEN, TileType_LIGHT,
+		TileType_DARK, TileType_KEY, TileType_L1, TileType_L2, TileType_L3, TileType_L4, TileType_L5, TileType_L6,
+	} {
+		tiles := [][]TileType{
+			make([]TileType, 6), make([]TileType, 7), make([]TileType, 8), make([]TileType, 9), make([]TileType, 10),
+			make([]TileType, 11),
+			make([]TileType, 10), make([]TileType, 9), make([]TileType, 8), make([]TileType, 7), make([]TileType, 6),
+		}
+		tiles[5][5] = tileType
+		board := NewBoard(tiles)
+		board.Board[FromXYPos(5, 5)].Lock = false
+		img := render.Image(&board, render.Options{HexSize: vision.SpriteSize})
+
+		cal := screen.Calibration{
+			Width:   img.Bounds().Dx(),
+			Height:  img.Bounds().Dy(),
+			OriginX: float64(img.Bounds().Dx()) / 2,
+			OriginY: float64(img.Bounds().Dy()) / 2,
+			HexSize: vision.SpriteSize,
+		}
+		learnt, err := vision.Learn(img, cal, tiles)
+		if err != nil {
+			panic(err)
+		}
+		sprite := image.NewRGBA(learnt[tileType].Bounds())
+		draw.Draw(sprite, sprite.Bounds(), learnt[tileType], image.Point{}, draw.Src)
+		sprites[tileType] = sprite
+	}
+	if err := vision.SaveSprites("sprites", sprites); err != nil {
+		panic(err)
+	}
+}
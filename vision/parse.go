@@ -0,0 +1,253 @@
+// Package vision reads a board from a screenshot of the game, by matching
+// each of the 91 tiles against reference sprites.
+//
+// It is experimental: the bundled sprites and the screenshot of the tests
+// are drawn by the render package, the parser was never checked on a real
+// capture of the game.
+package vision
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"sort"
+
+	. "sigmars-garden-solver/internal/srcs"
+	"sigmars-garden-solver/render"
+	"sigmars-garden-solver/screen"
+)
+
+type Result struct {
+	Tiles       [][]TileType // as accepted by NewBoard
+	Confidence  [][]float64  // between 0 (ambiguous) and 1, per tile
+	Calibration screen.Calibration
+}
+
+const (
+	sampleGrid = 12  // samples per side of the compared square
+	sampleArea = 0.6 // half side of the compared square, in tile radius
+	minGain    = 0.2 // darkest locked tile compared to its sprite
+	maxGain    = 1.25
+	keptCells  = 70 // percentage of the best matching cells in a distance
+)
+
+type features []float64
+
+type template struct {
+	tileType TileType
+	features features
+}
+
+// Parse classifies every tile of the screenshot. The origin of the
+// calibration is refined by a few pixels to absorb small offsets.
+func Parse(img image.Image, cal screen.Calibration, sprites Sprites) (Result, error) {
+	if err := checkResolution(img, cal); err != nil {
+		return Result{}, err
+	}
+	if _, ok := sprites[TileType_EMPTY]; !ok {
+		return Result{}, fmt.Errorf("vision: missing sprite for empty tiles")
+	}
+
+	var templates []template
+	spriteCal := screen.Calibration{OriginX: SpriteSize, OriginY: SpriteSize, HexSize: SpriteSize}
+	for _, tileType := range spriteTypes {
+		if sprite, ok := sprites[tileType]; ok {
+			templates = append(templates, template{tileType, extract(sprite, spriteCal, centerX, centerY)})
+		}
+	}
+
+	best := cal
+	bestScore := math.Inf(1)
+	reach := cal.HexSize / 4
+	step := math.Max(1, cal.HexSize/16)
+	for dy := -reach; dy <= reach; dy += step {
+		for dx := -reach; dx <= reach; dx += step {
+			try := cal
+			try.OriginX += dx
+			try.OriginY += dy
+			score := 0.
+			for i := range (Board{}).Board {
+				x, y := ToXYPos(i)
+				_, dist, _ := classify(extract(img, try, x, y), templates)
+				score += dist
+			}
+			if score < bestScore {
+				best, bestScore = try, score
+			}
+		}
+	}
+
+	result := Result{Calibration: best}
+	for i := range (Board{}).Board {
+		x, y := ToXYPos(i)
+		if y == 0 {
+			result.Tiles = append(result.Tiles, nil)
+			result.Confidence = append(result.Confidence, nil)
+		}
+		tileType, _, confidence := classify(extract(img, best, x, y), templates)
+		result.Tiles[x] = append(result.Tiles[x], tileType)
+		result.Confidence[x] = append(result.Confidence[x], confidence)
+	}
+	return result, nil
+}
+
+// LowConfidence returns the positions whose confidence is below threshold.
+func (this Result) LowConfidence(threshold float64) []Position {
+	var positions []Position
+	for x, line := range this.Confidence {
+		for y, confidence := range line {
+			if confidence < threshold {
+				positions = append(positions, Position{X: x, Y: y})
+			}
+		}
+	}
+	return positions
+}
+
+// Overlay draws the recognized tiles over the screenshot: each tile is
+// outlined with a color going from red to green with its confidence and
+// labeled with its recognized type.
+func (this Result) Overlay(img image.Image) *image.RGBA {
+	overlay := image.NewRGBA(img.Bounds())
+	draw.Draw(overlay, overlay.Bounds(), img, img.Bounds().Min, draw.Src)
+
+	cal := this.Calibration
+	for x, line := range this.Tiles {
+		for y, tileType := range line {
+			confidence := math.Max(0, math.Min(1, this.Confidence[x][y]))
+			c := color.RGBA{uint8(255 * (1 - confidence)), uint8(255 * confidence), 0, 0xff}
+			cx, cy := cal.Center(x, y)
+			outlineHex(overlay, cx, cy, cal.HexSize*0.9, c)
+			if label := render.TileLabel(tileType); label != "" {
+				render.DrawText(overlay, label, cx, cy+cal.HexSize/2, cal.HexSize/12, c)
+			}
+		}
+	}
+	return overlay
+}
+
+// the middle tile, at the origin of a calibration
+const centerX, centerY = 5, 5
+
+func checkResolution(img image.Image, cal screen.Calibration) error {
+	if err := cal.Valid(); err != nil {
+		return err
+	}
+	size := img.Bounds().Size()
+	if size.X != cal.Width || size.Y != cal.Height {
+		return fmt.Errorf("vision: screenshot is %dx%d but the calibration is for %dx%d", size.X, size.Y, cal.Width, cal.Height)
+	}
+	return nil
+}
+
+// extract samples the colors of a square centered on the tile (x, y), each
+// sample being the average of the pixels of its cell.
+func extract(img image.Image, cal screen.Calibration, x, y int) features {
+	cx, cy := cal.Center(x, y)
+	cell := 2 * sampleArea * cal.HexSize / sampleGrid
+	left, top := cx-sampleArea*cal.HexSize, cy-sampleArea*cal.HexSize
+	bounds := img.Bounds()
+
+	f := make(features, 0, sampleGrid*sampleGrid*3)
+	for sy := 0; sy < sampleGrid; sy++ {
+		for sx := 0; sx < sampleGrid; sx++ {
+			x0, y0 := int(left+float64(sx)*cell), int(top+float64(sy)*cell)
+			x1, y1 := int(left+float64(sx+1)*cell), int(top+float64(sy+1)*cell)
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+			if y1 <= y0 {
+				y1 = y0 + 1
+			}
+
+			var r, g, b, n float64
+			for py := y0; py < y1; py++ {
+				for px := x0; px < x1; px++ {
+					if !(image.Point{px, py}).In(bounds) {
+						continue
+					}
+					pr, pg, pb, _ := img.At(px, py).RGBA()
+					r, g, b, n = r+float64(pr>>8), g+float64(pg>>8), b+float64(pb>>8), n+1
+				}
+			}
+			if n > 0 {
+				r, g, b = r/n, g/n, b/n
+			}
+			f = append(f, r, g, b)
+		}
+	}
+	return f
+}
+
+// distance compares a sample to a template allowing the sample to be
+// uniformly darker, as locked tiles are. Both the gain and the error are
+// robust to the cells that differ the most, such as a label drawn in another
+// color on locked tiles.
+func distance(sample, tmpl features) float64 {
+	var ratios []float64
+	for i := 0; i < len(sample); i += 3 {
+		if t := tmpl[i] + tmpl[i+1] + tmpl[i+2]; t > 30 {
+			ratios = append(ratios, (sample[i]+sample[i+1]+sample[i+2])/t)
+		}
+	}
+	gain := 1.
+	if len(ratios) > 0 {
+		sort.Float64s(ratios)
+		gain = math.Max(minGain, math.Min(maxGain, ratios[len(ratios)/2]))
+	}
+
+	errors := make([]float64, 0, len(sample)/3)
+	for i := 0; i < len(sample); i += 3 {
+		var e float64
+		for c := i; c < i+3; c++ {
+			d := sample[c] - gain*tmpl[c]
+			e += d * d
+		}
+		errors = append(errors, e)
+	}
+	sort.Float64s(errors)
+
+	var sum float64
+	kept := len(errors) * keptCells / 100
+	for _, e := range errors[:kept] {
+		sum += e
+	}
+	return sum / float64(kept) / (3 * 255 * 255)
+}
+
+// classify returns the closest template, its distance and a confidence
+// computed from the gap with the closest template of another type.
+func classify(sample features, templates []template) (TileType, float64, float64) {
+	best, second := math.Inf(1), math.Inf(1)
+	var bestType TileType
+
+	for _, t := range templates {
+		d := distance(sample, t.features)
+		if d < best {
+			best, second, bestType = d, best, t.tileType
+		} else if d < second {
+			second = d
+		}
+	}
+	if second == 0 || math.IsInf(second, 1) {
+		return bestType, best, 0
+	}
+	return bestType, best, 1 - best/second
+}
+
+// outlineHex draws the border of a pointy-top hexagon.
+func outlineHex(img *image.RGBA, cx, cy, r float64, c color.RGBA) {
+	for i := 0; i < 6; i++ {
+		a1 := math.Pi/3*float64(i) - math.Pi/2
+		a2 := math.Pi/3*float64(i+1) - math.Pi/2
+		x1, y1 := cx+r*math.Cos(a1), cy+r*math.Sin(a1)
+		x2, y2 := cx+r*math.Cos(a2), cy+r*math.Sin(a2)
+		steps := int(math.Ceil(r)) + 1
+		for s := 0; s <= steps; s++ {
+			t := float64(s) / float64(steps)
+			img.SetRGBA(int(x1+(x2-x1)*t), int(y1+(y2-y1)*t), c)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package vision
+
+import (
+	"encoding/json"
+	"image"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+
+	. "sigmars-garden-solver/internal/srcs"
+	"sigmars-garden-solver/screen"
+)
+
+func loadPNG(t *testing.T, path string) image.Image {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	img, err := png.Decode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return img
+}
+
+// the screenshot is drawn by genscreenshot.go a few pixels away from the
+// default calibration, locked tiles darker
+func TestParseScreenshot(t *testing.T) {
+	img := loadPNG(t, "testdata/screenshot-1920x1080.png")
+	byteValue, err := ioutil.ReadFile("../inputs/input1.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want [][]TileType
+	if err := json.Unmarshal(byteValue, &want); err != nil {
+		t.Fatal(err)
+	}
+
+	cal := screen.Default(img.Bounds().Dx(), img.Bounds().Dy())
+	result, err := Parse(img, cal, DefaultSprites())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result.Tiles, want) {
+		t.Errorf("tiles:\n%v\nwant:\n%v", result.Tiles, want)
+	}
+	if low := result.LowConfidence(0.2); len(low) > 0 {
+		t.Errorf("low confidence at %v", low)
+	}
+	if result.Calibration.OriginX == cal.OriginX && result.Calibration.OriginY == cal.OriginY {
+		t.Error("the origin was not refined")
+	}
+}
+
+func TestParseResolution(t *testing.T) {
+	img := loadPNG(t, "testdata/screenshot-1920x1080.png")
+	if _, err := Parse(img, screen.Default(1280, 720), DefaultSprites()); err == nil {
+		t.Error("parsed a 1920x1080 screenshot with a 1280x720 calibration")
+	}
+}
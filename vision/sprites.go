@@ -0,0 +1,164 @@
+package vision
+
+import (
+	"embed"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	. "sigmars-garden-solver/internal/srcs"
+	"sigmars-garden-solver/screen"
+)
+
+//go:generate go run gensprites.go
+//go:generate go run genscreenshot.go
+
+//go:embed sprites/*.png
+var bundledSprites embed.FS
+
+// SpriteSize is the radius in pixels of the tiles in the sprite files, which
+// are squares of side 2*SpriteSize centered on the tile.
+const SpriteSize = 32
+
+// Sprites holds one reference image per tile type, empty tile included.
+type Sprites map[TileType]image.Image
+
+var spriteTypes = []TileType{
+	TileType_EMPTY,
+	TileType_WHITE,
+	TileType_CYAN,
+	TileType_ORANGE,
+	TileType_BLUE,
+	TileType_GREEN,
+	TileType_LIGHT,
+	TileType_DARK,
+	TileType_KEY,
+	TileType_L1,
+	TileType_L2,
+	TileType_L3,
+	TileType_L4,
+	TileType_L5,
+	TileType_L6,
+}
+
+func spriteFileName(tileType TileType) string {
+	if tileType == TileType_EMPTY {
+		return "empty.png"
+	}
+	return string(tileType) + ".png"
+}
+
+// DefaultSprites returns the sprites bundled with the package.
+func DefaultSprites() Sprites {
+	sub, err := fs.Sub(bundledSprites, "sprites")
+	if err != nil {
+		panic(err)
+	}
+	sprites, err := loadSprites(sub)
+	if err != nil {
+		panic(err)
+	}
+	return sprites
+}
+
+// LoadSprites reads the sprites of a directory written by SaveSprites. Tile
+// types missing from the directory keep their bundled sprite.
+func LoadSprites(dir string) (Sprites, error) {
+	sprites, err := loadSprites(os.DirFS(dir))
+	if err != nil {
+		return nil, err
+	}
+	for tileType, sprite := range DefaultSprites() {
+		if _, ok := sprites[tileType]; !ok {
+			sprites[tileType] = sprite
+		}
+	}
+	return sprites, nil
+}
+
+func loadSprites(fsys fs.FS) (Sprites, error) {
+	sprites := Sprites{}
+	for _, tileType := range spriteTypes {
+		f, err := fsys.Open(spriteFileName(tileType))
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		img, err := png.Decode(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("sprite %s: %w", spriteFileName(tileType), err)
+		}
+		sprites[tileType] = img
+	}
+	return sprites, nil
+}
+
+// SaveSprites writes the sprites in dir, one PNG file per tile type.
+func SaveSprites(dir string, sprites Sprites) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for tileType, sprite := range sprites {
+		f, err := os.Create(filepath.Join(dir, spriteFileName(tileType)))
+		if err != nil {
+			return err
+		}
+		err = png.Encode(f, sprite)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Learn cuts the sprites out of a screenshot of a known board, so that the
+// bundled sprites can be replaced by captures of the real game. Only
+// tiles free of neighbors are used, metals included whatever their stage,
+// and the first one found of each type is kept.
+func Learn(img image.Image, cal screen.Calibration, tiles [][]TileType) (Sprites, error) {
+	if err := checkResolution(img, cal); err != nil {
+		return nil, err
+	}
+	board := NewBoard(tiles)
+	board.AlchemyStage = AlchemyStage_5
+	for i := range board.Board {
+		board.CheckLockState(ToXYPos(i))
+	}
+	sprites := Sprites{}
+
+	for i, tile := range board.Board {
+		if _, ok := sprites[tile.Type]; ok || tile.Lock {
+			continue
+		}
+		x, y := ToXYPos(i)
+		sprites[tile.Type] = cutSprite(img, cal, x, y)
+	}
+
+	return sprites, nil
+}
+
+// cutSprite resamples the tile (x, y) of img to the sprite size.
+func cutSprite(img image.Image, cal screen.Calibration, x, y int) image.Image {
+	cx, cy := cal.Center(x, y)
+	sprite := image.NewRGBA(image.Rect(0, 0, 2*SpriteSize, 2*SpriteSize))
+	draw.Draw(sprite, sprite.Bounds(), image.Black, image.Point{}, draw.Src)
+
+	scale := cal.HexSize / SpriteSize
+	for sy := 0; sy < 2*SpriteSize; sy++ {
+		for sx := 0; sx < 2*SpriteSize; sx++ {
+			px := int(cx + (float64(sx-SpriteSize)+0.5)*scale)
+			py := int(cy + (float64(sy-SpriteSize)+0.5)*scale)
+			if (image.Point{px, py}).In(img.Bounds()) {
+				sprite.Set(sx, sy, img.At(px, py))
+			}
+		}
+	}
+	return sprite
+}
@@ -0,0 +1,142 @@
+//go:build js && wasm
+
+// Command wasm exposes the solver to JavaScript, as the functions of the
+// global object sigmar. They take and return JSON strings, the responses of
+// package api shared with the HTTP server:
+//   - sigmar.solve(board, maxChecks?): the solution of the board,
+//   - sigmar.validate(board): the problems found without searching the board,
+//   - sigmar.hint(board, maxChecks?): a move that keeps the board solvable,
+//   - sigmar.render(board, moves?, step?): the board as SVG, after playing
+//     the moves before step, with the tiles of the move step outlined.
+//
+// Errors are returned as {"error": {"code": ..., "message": ...}}. The
+// search runs on the thread of the caller, bound by maxChecks only.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"syscall/js"
+
+	"sigmars-garden-solver/api"
+	. "sigmars-garden-solver/internal/srcs"
+	"sigmars-garden-solver/render"
+)
+
+const (
+	defaultMaxChecks     = 10000000
+	defaultHintMaxChecks = 1000000
+)
+
+type RenderResponse struct {
+	SVG string `json:"svg"`
+}
+
+func main() {
+	js.Global().Set("sigmar", js.ValueOf(map[string]any{
+		"solve":    export(solve),
+		"validate": export(validate),
+		"hint":     export(hint),
+		"render":   export(renderBoard),
+	}))
+	select {}
+}
+
+// export turns f in a JavaScript function returning its response, or its
+// error, as JSON. Panics are returned as internal errors.
+func export(f func(args []js.Value) (any, *api.Error)) js.Func {
+	return js.FuncOf(func(_ js.Value, args []js.Value) (result any) {
+		defer func() {
+			if p := recover(); p != nil {
+				result = toJSON(api.ErrorResponse(api.NewError(http.StatusInternalServerError, "internal", "%v", p)))
+			}
+		}()
+		response, err := f(args)
+		if err != nil {
+			return toJSON(api.ErrorResponse(err))
+		}
+		return toJSON(response)
+	})
+}
+
+func solve(args []js.Value) (any, *api.Error) {
+	board, err := parseBoard(args)
+	if err != nil {
+		return nil, err
+	}
+	return api.Solve(context.Background(), board, intArg(args, 1, defaultMaxChecks))
+}
+
+func validate(args []js.Value) (any, *api.Error) {
+	board, err := parseBoard(args)
+	if err != nil {
+		return nil, err
+	}
+	return api.Validate(board), nil
+}
+
+func hint(args []js.Value) (any, *api.Error) {
+	board, err := parseBoard(args)
+	if err != nil {
+		return nil, err
+	}
+	return api.Hint(context.Background(), board, intArg(args, 1, defaultHintMaxChecks))
+}
+
+func renderBoard(args []js.Value) (any, *api.Error) {
+	board, err := parseBoard(args)
+	if err != nil {
+		return nil, err
+	}
+
+	var moves []api.Move
+	if len(args) > 1 && args[1].Type() == js.TypeString {
+		if err := json.Unmarshal([]byte(args[1].String()), &moves); err != nil {
+			return nil, api.NewError(http.StatusBadRequest, "invalid_json", "moves: %v", err)
+		}
+	}
+	step := int(intArg(args, 2, int64(len(moves))))
+
+	var opts render.Options
+	for i, move := range moves {
+		if i == step {
+			opts.Marked = []Position{{X: move.X1, Y: move.Y1}, {X: move.X2, Y: move.Y2}}
+			break
+		}
+		if _, err := board.Move(move.X1, move.Y1, move.X2, move.Y2); err != nil {
+			return nil, api.NewError(http.StatusUnprocessableEntity, "illegal_move", "move %d: %v", i+1, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := render.SVG(&buf, &board, opts); err != nil {
+		return nil, api.NewError(http.StatusInternalServerError, "internal", "%v", err)
+	}
+	return RenderResponse{SVG: buf.String()}, nil
+}
+
+func parseBoard(args []js.Value) (Board, *api.Error) {
+	if len(args) == 0 || args[0].Type() != js.TypeString {
+		return Board{}, api.NewError(http.StatusBadRequest, "missing_board", "the board must be given as a JSON string")
+	}
+	var tiles [][]TileType
+	if err := json.Unmarshal([]byte(args[0].String()), &tiles); err != nil {
+		return Board{}, api.NewError(http.StatusBadRequest, "invalid_json", "%v", err)
+	}
+	return api.ParseBoard(tiles)
+}
+
+// intArg returns the argument i if it is a number, def otherwise.
+func intArg(args []js.Value, i int, def int64) int64 {
+	if len(args) > i && args[i].Type() == js.TypeNumber {
+		return int64(args[i].Float())
+	}
+	return def
+}
+
+func toJSON(v any) string {
+	byteValue, _ := json.Marshal(v)
+	return string(byteValue)
+}